@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CommandOutput is the truncated result of the last management command run for a CR.
+type CommandOutput struct {
+	// ExitCode of the command. -1 means the command could not be run to completion.
+	ExitCode int32 `json:"exitCode"`
+	// Stdout holds the tail of the command's standard output.
+	// +optional
+	Stdout string `json:"stdout,omitempty"`
+	// Stderr holds the tail of the command's standard error.
+	// +optional
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// CommandPhase is the lifecycle phase of a one-shot management command.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type CommandPhase string
+
+const (
+	// PhasePending means the command has not started yet, e.g. no Django pod is available.
+	PhasePending CommandPhase = "Pending"
+	// PhaseRunning means the command is currently executing.
+	PhaseRunning CommandPhase = "Running"
+	// PhaseSucceeded means the command finished with exit code 0.
+	PhaseSucceeded CommandPhase = "Succeeded"
+	// PhaseFailed means the last attempt to run the command failed.
+	PhaseFailed CommandPhase = "Failed"
+)
+
+// Condition types set on the one-shot command kinds.
+const (
+	// ConditionReady is True once the command has completed successfully.
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while the command is waiting to run or running.
+	ConditionProgressing = "Progressing"
+	// ConditionFailed is True when the last attempt failed.
+	ConditionFailed = "Failed"
+)
+
+// CommandStatus is the observed state shared by all one-shot management command kinds.
+type CommandStatus struct {
+	// Phase is a summary of where the command is in its lifecycle.
+	// +optional
+	Phase CommandPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the .metadata.generation last acted upon by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Attempts is the number of times the command has been started.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastError is the error message of the last failed attempt.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// CompletionTime is when the command last reached Succeeded or Failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// NextRetryTime is the earliest time the next attempt will start after a failure.
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+	// Output of the last run.
+	// +optional
+	Output *CommandOutput `json:"output,omitempty"`
+	// ImageDigest is the image, as reported in the container's imageID, the command last
+	// succeeded with.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// Conditions describe the current state of the command (Ready, Progressing, Failed).
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// RunnerType selects how management commands are executed.
+// +kubebuilder:validation:Enum=Exec;Job
+type RunnerType string
+
+const (
+	// RunnerExec runs the command by exec'ing into a running Django pod.
+	RunnerExec RunnerType = "Exec"
+	// RunnerJob runs the command in a dedicated Job built from the Django pod's template.
+	RunnerJob RunnerType = "Job"
+)
+
+// RetryPolicy controls how failed management commands are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts before the CR is marked as Failed.
+	// Zero or unset retries forever.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// BackoffBase is the delay before the first retry; it doubles on every further
+	// failure. Defaults to 10s.
+	// +optional
+	BackoffBase *metav1.Duration `json:"backoffBase,omitempty"`
+	// BackoffCap is the maximum delay between two attempts. Defaults to 5m.
+	// +optional
+	BackoffCap *metav1.Duration `json:"backoffCap,omitempty"`
+}
+
+// RetentionPolicy controls when finished command CRs are garbage collected. Unset or
+// zero fields disable the corresponding rule. CRs that are still Pending or Running
+// are never pruned.
+type RetentionPolicy struct {
+	// SuccessfulHistoryLimit keeps only this many of the most recent Succeeded CRs of a kind.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulHistoryLimit *int32 `json:"successfulHistoryLimit,omitempty"`
+	// FailedHistoryLimit keeps only this many of the most recent Failed CRs of a kind.
+	// Failed CRs are kept forever unless this or TTLSecondsAfterFinished is set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedHistoryLimit *int32 `json:"failedHistoryLimit,omitempty"`
+	// TTLSecondsAfterFinished deletes a CR this many seconds after it Succeeded or Failed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// CommandOptions are the spec fields shared by the one-shot command kinds.
+type CommandOptions struct {
+	// Runner overrides the operator-wide runner (Exec or Job) for this CR.
+	// +optional
+	Runner RunnerType `json:"runner,omitempty"`
+	// Container overrides the operator-wide container the command runs in.
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Image runs the command with this image instead of the Django pod's, e.g. to migrate
+	// before a new release is rolled out. Only the Job runner can do so, so it implies runner Job.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// RetryPolicy controls how failed runs are retried.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Retention overrides the operator-wide retention policy for this CR.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+}
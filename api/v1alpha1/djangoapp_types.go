@@ -17,17 +17,193 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// DjangoAppSpec defines the desired state of DjangoApp.
+// MigrationOrder selects when automatic migrations run relative to the Helm upgrade.
+// +kubebuilder:validation:Enum=PreUpgrade;PostUpgrade
+type MigrationOrder string
+
+const (
+	// MigrationOrderPreUpgrade migrates with the new image before it is rolled out.
+	MigrationOrderPreUpgrade MigrationOrder = "PreUpgrade"
+	// MigrationOrderPostUpgrade migrates in the new pods once they are ready.
+	MigrationOrderPostUpgrade MigrationOrder = "PostUpgrade"
+)
+
+// DjangoAppSpec defines the desired state of DjangoApp. The typed fields are mapped onto
+// the values of the embedded chart and take precedence over the same keys in Values.
 type DjangoAppSpec struct {
+	// Image of the Django and Celery containers.
+	// +optional
+	Image *AppImage `json:"image,omitempty"`
+	// Replicas of the Django server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Resources of the Django server container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Env is set on the Django and Celery containers. It replaces the chart's global.env,
+	// which references the chart's example Secrets.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Ingress of the Django server.
+	// +optional
+	Ingress *AppIngress `json:"ingress,omitempty"`
+	// Probes of the Django server container.
+	// +optional
+	Probes *AppProbes `json:"probes,omitempty"`
+	// Celery workers and beat.
+	// +optional
+	Celery *AppCelery `json:"celery,omitempty"`
+	// Values are passed through to the chart, for everything the typed fields don't cover.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	Values *apiextv1.JSON `json:"values,omitempty"`
+	// AutoMigrate runs migrations whenever the deployed image changes.
+	// +optional
+	AutoMigrate *AutoMigrate `json:"autoMigrate,omitempty"`
+}
+
+// AppImage is the image of the Django and Celery containers.
+type AppImage struct {
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// +optional
+	Tag string `json:"tag,omitempty"`
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// AppIngress is the Ingress of the Django server.
+type AppIngress struct {
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Annotations of the Ingress, e.g. kubernetes.io/ingress.class.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// +optional
+	Hosts []IngressHost `json:"hosts,omitempty"`
+	// +optional
+	TLS []IngressTLS `json:"tls,omitempty"`
+}
+
+// IngressHost routes paths of a host to the Django server.
+type IngressHost struct {
+	Host string `json:"host"`
+	// +kubebuilder:default={"/"}
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+}
+
+// IngressTLS terminates TLS for hosts with the certificate of a Secret.
+type IngressTLS struct {
+	Hosts      []string `json:"hosts"`
+	SecretName string   `json:"secretName"`
+}
+
+// AppProbes are the HTTP probes of the Django server container.
+type AppProbes struct {
+	// Enabled turns both probes on or off.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// +optional
+	Readiness *HTTPProbe `json:"readiness,omitempty"`
+	// +optional
+	Liveness *HTTPProbe `json:"liveness,omitempty"`
+}
+
+// HTTPProbe is a probe sending a GET request to the Django server.
+type HTTPProbe struct {
+	// Path requested, e.g. /healthz.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is only used by the liveness probe.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is only used by the liveness probe.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// AppCelery configures the Celery workers and beat. Setting it enables them.
+type AppCelery struct {
+	// Workers run one Deployment per queue.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Workers []CeleryWorker `json:"workers,omitempty"`
+	// Beat runs the Celery beat scheduler.
+	// +optional
+	Beat *CeleryBeat `json:"beat,omitempty"`
+}
+
+// CeleryWorker is a Deployment of Celery workers consuming a queue.
+type CeleryWorker struct {
+	// Name of the queue; the Deployment is named <app>-celery-work-<name>.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Command overrides the chart's celery worker command.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// CeleryBeat is the Celery beat scheduler.
+type CeleryBeat struct {
+	// Command overrides the chart's celery beat command.
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// AutoMigrate configures the migrations run on every image change of a DjangoApp.
+type AutoMigrate struct {
+	// Order PreUpgrade migrates in a Job running the new image and keeps the release on the
+	// previously migrated image until it succeeds. PostUpgrade migrates in the new pods
+	// once they are ready.
+	// +kubebuilder:default=PostUpgrade
+	// +optional
+	Order MigrationOrder `json:"order,omitempty"`
+	// CollectStatic also runs collectstatic once the migrations succeed.
+	// +optional
+	CollectStatic bool `json:"collectStatic,omitempty"`
+	// RetryPolicy of the DjangoMigrate and DjangoStatic created for every image.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 }
 
+// Condition types reported on a DjangoApp. Deployed and ReleaseFailed are maintained by
+// the Helm reconciler, Available by the operator.
+const (
+	// AppConditionDeployed is True once a release of the chart is deployed.
+	AppConditionDeployed = "Deployed"
+	// AppConditionAvailable is True when every Django and Celery workload has all its
+	// replicas ready.
+	AppConditionAvailable = "Available"
+	// AppConditionReleaseFailed is True when the last install or upgrade failed.
+	AppConditionReleaseFailed = "ReleaseFailed"
+)
+
 // DjangoAppStatus defines the observed state of DjangoApp.
 // +kubebuilder:pruning:PreserveUnknownFields
 // +kubebuilder:validation:EmbeddedResource
@@ -35,10 +211,103 @@ type DjangoAppStatus struct {
 	// Created is when the chart was first installed.
 	// +optional
 	Created *metav1.Time `json:"created,omitempty"`
+	// Release is the deployed Helm release.
+	// +optional
+	Release *ReleaseStatus `json:"release,omitempty"`
+	// Django reports the replicas of the Django server workloads.
+	// +optional
+	Django *WorkloadStatus `json:"django,omitempty"`
+	// Celery reports the replicas of the Celery worker and beat workloads.
+	// +optional
+	Celery *WorkloadStatus `json:"celery,omitempty"`
+	// Conditions describe the state of the release (Deployed, Available, ReleaseFailed).
+	// They follow the format of the Helm reconciler, which also writes them.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []AppCondition `json:"conditions,omitempty"`
+	// AutoMigrate reports the automatic migrations of the deployed image.
+	// +optional
+	AutoMigrate *AutoMigrateStatus `json:"autoMigrate,omitempty"`
+}
+
+// AppCondition is a condition of a DjangoApp. Unlike metav1.Condition, its reason is
+// optional, as the Helm reconciler leaves it empty.
+type AppCondition struct {
+	// Type of the condition, e.g. Available.
+	Type string `json:"type"`
+	// Status of the condition: True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ReleaseStatus describes the deployed Helm release of a DjangoApp.
+type ReleaseStatus struct {
+	// Name of the release.
+	Name string `json:"name"`
+	// Revision of the release, incremented by every upgrade.
+	Revision int `json:"revision"`
+	// ChartVersion is the version of the chart the release was deployed from.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// ValuesHash is the SHA-256 of the values the release was deployed with.
+	// +optional
+	ValuesHash string `json:"valuesHash,omitempty"`
+	// LastDeployed is when the revision was deployed.
+	// +optional
+	LastDeployed *metav1.Time `json:"lastDeployed,omitempty"`
+}
+
+// WorkloadStatus sums the replicas of the Deployments and StatefulSets of one part of a
+// release.
+type WorkloadStatus struct {
+	// Replicas is the desired number of replicas.
+	Replicas int32 `json:"replicas"`
+	// ReadyReplicas is the number of replicas that are ready.
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// UpdatedReplicas is the number of replicas running the latest template.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+}
+
+// AutoMigrateStatus is the state of the automatic migrations of a DjangoApp.
+type AutoMigrateStatus struct {
+	// Image is the image of the release the current run migrates for.
+	Image string `json:"image"`
+	// Phase summarises the DjangoMigrate and, if requested, DjangoStatic of Image.
+	// +optional
+	Phase CommandPhase `json:"phase,omitempty"`
+	// Migration is the DjangoMigrate created for Image.
+	// +optional
+	Migration string `json:"migration,omitempty"`
+	// Static is the DjangoStatic created for Image.
+	// +optional
+	Static string `json:"static,omitempty"`
+	// ImageDigest is the image digest the migrations of Image ran against.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+	// MigratedImage is the last image whose automatic run succeeded.
+	// +optional
+	MigratedImage string `json:"migratedImage,omitempty"`
+	// MigratedAt is when the automatic run of MigratedImage succeeded.
+	// +optional
+	MigratedAt *metav1.Time `json:"migratedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.release.revision`
+// +kubebuilder:printcolumn:name="Deployed",type=string,JSONPath=`.status.conditions[?(@.type=="Deployed")].status`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
+// +kubebuilder:printcolumn:name="Django",type=string,JSONPath=`.status.django.readyReplicas`,priority=1
+// +kubebuilder:printcolumn:name="Celery",type=string,JSONPath=`.status.celery.readyReplicas`,priority=1
+// +kubebuilder:printcolumn:name="Chart",type=string,JSONPath=`.status.release.chartVersion`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DjangoApp is the Schema for the djangoapps API.
 type DjangoApp struct {
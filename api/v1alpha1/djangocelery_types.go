@@ -25,15 +25,26 @@ type DjangoCelerySpec struct {
 	App    string `json:"app"`
 	Worker string `json:"worker,omitempty"`
 	Task   string `json:"task,omitempty"`
+
+	CommandOptions `json:",inline"`
 }
 
 // DjangoCeleryStatus defines the observed state of DjangoCelery.
 type DjangoCeleryStatus struct {
-	Executed metav1.Time `json:"executed,omitempty"`
+	// Executed is when the celery command finished successfully.
+	// +optional
+	Executed *metav1.Time `json:"executed,omitempty"`
+
+	CommandStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Executed",type=date,JSONPath=`.status.executed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DjangoCelery is the Schema for the djangoceleries API.
 type DjangoCelery struct {
@@ -53,6 +64,16 @@ type DjangoCeleryList struct {
 	Items           []DjangoCelery `json:"items"`
 }
 
+// GetCommandOptions returns the options shared by the one-shot command kinds.
+func (dc *DjangoCelery) GetCommandOptions() *CommandOptions {
+	return &dc.Spec.CommandOptions
+}
+
+// GetCommandStatus returns the status shared by the one-shot command kinds.
+func (dc *DjangoCelery) GetCommandStatus() *CommandStatus {
+	return &dc.Status.CommandStatus
+}
+
 func init() {
 	SchemeBuilder.Register(&DjangoCelery{}, &DjangoCeleryList{})
 }
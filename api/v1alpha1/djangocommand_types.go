@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CommandEnvVar is an environment variable set for a DjangoCommand.
+type CommandEnvVar struct {
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// DjangoCommandSpec defines the desired state of DjangoCommand.
+type DjangoCommandSpec struct {
+	// Command is the manage.py command to run, e.g. clearsessions.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	Command string `json:"command"`
+	// Args are passed to the command as-is, without a shell.
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Env is added to the environment of the command. Values are visible in the
+	// process list of the pod with the Exec runner; do not put secrets here.
+	// +optional
+	Env []CommandEnvVar `json:"env,omitempty"`
+
+	CommandOptions `json:",inline"`
+}
+
+// DjangoCommandStatus defines the observed state of DjangoCommand.
+type DjangoCommandStatus struct {
+	// Executed is when the command finished successfully.
+	// +optional
+	Executed *metav1.Time `json:"executed,omitempty"`
+
+	CommandStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Command",type=string,JSONPath=`.spec.command`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Executed",type=date,JSONPath=`.status.executed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DjangoCommand is the Schema for the djangocommands API.
+type DjangoCommand struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DjangoCommandSpec   `json:"spec,omitempty"`
+	Status DjangoCommandStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DjangoCommandList contains a list of DjangoCommand.
+type DjangoCommandList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DjangoCommand `json:"items"`
+}
+
+// GetCommandOptions returns the options shared by the one-shot command kinds.
+func (dc *DjangoCommand) GetCommandOptions() *CommandOptions {
+	return &dc.Spec.CommandOptions
+}
+
+// GetCommandStatus returns the status shared by the one-shot command kinds.
+func (dc *DjangoCommand) GetCommandStatus() *CommandStatus {
+	return &dc.Status.CommandStatus
+}
+
+func init() {
+	SchemeBuilder.Register(&DjangoCommand{}, &DjangoCommandList{})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how a DjangoCronJob treats runs that overlap.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyAllow lets runs overlap.
+	ConcurrencyAllow ConcurrencyPolicy = "Allow"
+	// ConcurrencyForbid skips a run while the previous one is still in flight.
+	ConcurrencyForbid ConcurrencyPolicy = "Forbid"
+	// ConcurrencyReplace deletes the in-flight run and starts the new one.
+	ConcurrencyReplace ConcurrencyPolicy = "Replace"
+)
+
+// DjangoCronJobTemplate is the spec of the runs a DjangoCronJob creates. Exactly one
+// field must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.migrate) ? 1 : 0) + (has(self.static) ? 1 : 0) + (has(self.celery) ? 1 : 0) + (has(self.command) ? 1 : 0) == 1",message="exactly one of migrate, static, celery or command must be set"
+type DjangoCronJobTemplate struct {
+	// +optional
+	Migrate *DjangoMigrateSpec `json:"migrate,omitempty"`
+	// +optional
+	Static *DjangoStaticSpec `json:"static,omitempty"`
+	// +optional
+	Celery *DjangoCelerySpec `json:"celery,omitempty"`
+	// +optional
+	Command *DjangoCommandSpec `json:"command,omitempty"`
+}
+
+// DjangoCronJobSpec defines the desired state of DjangoCronJob.
+type DjangoCronJobSpec struct {
+	// Schedule in cron format, e.g. "0 3 * * *". Descriptors such as "@daily" are accepted too.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// StartingDeadlineSeconds is how late a run may start after its scheduled time;
+	// runs missed by more than this are skipped.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// ConcurrencyPolicy says what to do when a run is due while the previous one is in flight.
+	// Replace deletes the in-flight CR, but does not interrupt a command already executing.
+	// +kubebuilder:default=Allow
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend stops new runs from being scheduled; in-flight runs are not affected.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+	// SuccessfulRunsHistoryLimit is how many Succeeded runs to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=3
+	// +optional
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+	// FailedRunsHistoryLimit is how many Failed runs to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+	// Template is the run created on every schedule.
+	Template DjangoCronJobTemplate `json:"template"`
+}
+
+// DjangoCronJobStatus defines the observed state of DjangoCronJob.
+type DjangoCronJobStatus struct {
+	// Active lists the runs that are still Pending or Running.
+	// +optional
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// LastScheduleTime is when a run was last scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is when a run last Succeeded.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Conditions report problems with the schedule or the template.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DjangoCronJob is the Schema for the djangocronjobs API.
+type DjangoCronJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DjangoCronJobSpec   `json:"spec,omitempty"`
+	Status DjangoCronJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DjangoCronJobList contains a list of DjangoCronJob.
+type DjangoCronJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DjangoCronJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DjangoCronJob{}, &DjangoCronJobList{})
+}
@@ -20,20 +20,109 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// MigrationMode selects whether a DjangoMigrate applies migrations or only plans them.
+// +kubebuilder:validation:Enum=Apply;Plan
+type MigrationMode string
+
+const (
+	// MigrationModeApply runs migrate straight away.
+	MigrationModeApply MigrationMode = "Apply"
+	// MigrationModePlan runs migrate --plan and waits for approval before applying.
+	MigrationModePlan MigrationMode = "Plan"
+)
+
+// MigrationApprovedAnnotation approves the plan of a DjangoMigrate in Plan mode when set to "true".
+const MigrationApprovedAnnotation = "django.djangooperator/approved"
+
+// MigrationRollbackPrevious, as spec.rollbackTo, rolls back the most recently applied
+// DjangoMigrate of the namespace.
+const MigrationRollbackPrevious = "previous"
+
+// MigrationZero is the migrate target unapplying every migration of an app.
+const MigrationZero = "zero"
+
 // DjangoMigrateSpec defines the desired state of DjangoMigrate.
+// +kubebuilder:validation:XValidation:rule="!has(self.rollbackTo) || (!has(self.app) && !has(self.migration))",message="rollbackTo cannot be combined with app or migration"
 type DjangoMigrateSpec struct {
 	Fake      bool   `json:"fake,omitempty"`
 	App       string `json:"app,omitempty"`
 	Migration string `json:"migration,omitempty"`
+	// Mode Plan only reports the pending migrations in status.plan; they are applied once
+	// the CR is annotated with django.djangooperator/approved=true or mode is set to Apply.
+	// +kubebuilder:default=Apply
+	// +optional
+	Mode MigrationMode `json:"mode,omitempty"`
+	// RollbackTo reverts the migrations applied by another DjangoMigrate, named here, or by
+	// the most recently applied one when set to "previous". Every app it migrated is taken
+	// back to the state recorded in its status.preApply.
+	// +optional
+	RollbackTo string `json:"rollbackTo,omitempty"`
+
+	CommandOptions `json:",inline"`
 }
 
 // DjangoMigrateStatus defines the observed state of DjangoMigrate.
 type DjangoMigrateStatus struct {
-	Applied metav1.Time `json:"applied"`
+	// Applied is when the migration finished successfully.
+	// +optional
+	Applied *metav1.Time `json:"applied,omitempty"`
+	// Plan is the result of the last migrate --plan, in Plan mode.
+	// +optional
+	Plan *MigrationPlan `json:"plan,omitempty"`
+	// PreApply is the migration state of the apps about to be migrated, recorded right
+	// before applying. A later DjangoMigrate with rollbackTo restores it.
+	// +optional
+	PreApply *MigrationState `json:"preApply,omitempty"`
+	// RollbackOf is the DjangoMigrate being rolled back, once spec.rollbackTo is resolved.
+	// +optional
+	RollbackOf string `json:"rollbackOf,omitempty"`
+
+	CommandStatus `json:",inline"`
+}
+
+// MigrationPlan lists the migrations migrate would apply.
+type MigrationPlan struct {
+	// Generation is the spec generation the plan was computed for.
+	Generation int64 `json:"generation"`
+	// PlannedAt is when the plan was computed.
+	PlannedAt metav1.Time `json:"plannedAt"`
+	// Pending lists, per app and in order, the migrations migrate would apply.
+	// +optional
+	Pending []AppMigrations `json:"pending,omitempty"`
+}
+
+// AppMigrations is a list of migrations of one Django app.
+type AppMigrations struct {
+	App        string   `json:"app"`
+	Migrations []string `json:"migrations,omitempty"`
+}
+
+// MigrationState is the migration state of some Django apps at a point in time.
+type MigrationState struct {
+	// Generation is the spec generation the state was recorded for.
+	Generation int64 `json:"generation"`
+	// RecordedAt is when the state was recorded.
+	RecordedAt metav1.Time `json:"recordedAt"`
+	// Apps lists the last applied migration of every app, zero when it had none.
+	// +optional
+	Apps []AppMigrationState `json:"apps,omitempty"`
+}
+
+// AppMigrationState is the last applied migration of one Django app.
+type AppMigrationState struct {
+	App       string `json:"app"`
+	Migration string `json:"migration"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`,priority=1
+// +kubebuilder:printcolumn:name="Rollback-Of",type=string,JSONPath=`.status.rollbackOf`,priority=1
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Applied",type=date,JSONPath=`.status.applied`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DjangoMigrate is the Schema for the djangomigrates API.
 type DjangoMigrate struct {
@@ -53,6 +142,16 @@ type DjangoMigrateList struct {
 	Items           []DjangoMigrate `json:"items"`
 }
 
+// GetCommandOptions returns the options shared by the one-shot command kinds.
+func (dm *DjangoMigrate) GetCommandOptions() *CommandOptions {
+	return &dm.Spec.CommandOptions
+}
+
+// GetCommandStatus returns the status shared by the one-shot command kinds.
+func (dm *DjangoMigrate) GetCommandStatus() *CommandStatus {
+	return &dm.Status.CommandStatus
+}
+
 func init() {
 	SchemeBuilder.Register(&DjangoMigrate{}, &DjangoMigrateList{})
 }
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineFailurePolicy decides what a DjangoPipeline does when one of its steps fails.
+// +kubebuilder:validation:Enum=StopOnFailure;ContinueOnError
+type PipelineFailurePolicy string
+
+const (
+	// PipelineStopOnFailure skips the remaining steps once a step has failed.
+	PipelineStopOnFailure PipelineFailurePolicy = "StopOnFailure"
+	// PipelineContinueOnError runs the remaining steps anyway; the pipeline still fails.
+	PipelineContinueOnError PipelineFailurePolicy = "ContinueOnError"
+)
+
+// PipelineStepPhase is the lifecycle phase of a step of a DjangoPipeline.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Skipped
+type PipelineStepPhase string
+
+const (
+	// StepPending means the step has not started, or its run waits to be executed.
+	StepPending PipelineStepPhase = "Pending"
+	// StepRunning means the run of the step is executing.
+	StepRunning PipelineStepPhase = "Running"
+	// StepSucceeded means the run of the step Succeeded.
+	StepSucceeded PipelineStepPhase = "Succeeded"
+	// StepFailed means the run of the step Failed.
+	StepFailed PipelineStepPhase = "Failed"
+	// StepSkipped means the step was not run because an earlier step failed.
+	StepSkipped PipelineStepPhase = "Skipped"
+)
+
+// DjangoPipelineStep is one step of a DjangoPipeline. Exactly one of the run specs must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.migrate) ? 1 : 0) + (has(self.static) ? 1 : 0) + (has(self.celery) ? 1 : 0) + (has(self.user) ? 1 : 0) + (has(self.command) ? 1 : 0) == 1",message="exactly one of migrate, static, celery, user or command must be set"
+type DjangoPipelineStep struct {
+	// Name identifies the step; its run is named <pipeline>-<name>.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// +optional
+	Migrate *DjangoMigrateSpec `json:"migrate,omitempty"`
+	// +optional
+	Static *DjangoStaticSpec `json:"static,omitempty"`
+	// +optional
+	Celery *DjangoCelerySpec `json:"celery,omitempty"`
+	// +optional
+	User *DjangoUserSpec `json:"user,omitempty"`
+	// +optional
+	Command *DjangoCommandSpec `json:"command,omitempty"`
+}
+
+// DjangoPipelineSpec defines the desired state of DjangoPipeline.
+type DjangoPipelineSpec struct {
+	// Steps run one after the other, each once the previous one has finished.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=map
+	// +listMapKey=name
+	Steps []DjangoPipelineStep `json:"steps"`
+	// FailurePolicy decides whether the steps after a failed one still run.
+	// +kubebuilder:default=StopOnFailure
+	// +optional
+	FailurePolicy PipelineFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// PipelineStepStatus is the observed state of a step of a DjangoPipeline.
+type PipelineStepStatus struct {
+	// Name of the step.
+	Name string `json:"name"`
+	// Kind of the run of the step, e.g. DjangoMigrate.
+	Kind string `json:"kind"`
+	// Run is the name of the CR running the step.
+	// +optional
+	Run string `json:"run,omitempty"`
+	// Phase of the step.
+	Phase PipelineStepPhase `json:"phase"`
+	// StartTime is when the run of the step was created.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the run of the step Succeeded or Failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message is the last error of a failed run.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DjangoPipelineStatus defines the observed state of DjangoPipeline.
+type DjangoPipelineStatus struct {
+	// Phase is Running until every step has finished, then Succeeded or, if a step
+	// failed, Failed.
+	// +optional
+	Phase CommandPhase `json:"phase,omitempty"`
+	// CurrentStep is the name of the step being run.
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+	// Steps reports every step of the pipeline, in order.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Steps []PipelineStepStatus `json:"steps,omitempty"`
+	// CompletionTime is when the pipeline Succeeded or Failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Conditions describe the current state of the pipeline (Ready, Progressing, Failed).
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=`.status.currentStep`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DjangoPipeline is the Schema for the djangopipelines API. It runs an ordered list of
+// management commands once; its spec cannot be changed afterwards.
+type DjangoPipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable; create a new DjangoPipeline to run again"
+	Spec   DjangoPipelineSpec   `json:"spec,omitempty"`
+	Status DjangoPipelineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DjangoPipelineList contains a list of DjangoPipeline.
+type DjangoPipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DjangoPipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DjangoPipeline{}, &DjangoPipelineList{})
+}
@@ -21,17 +21,26 @@ import (
 )
 
 // DjangoStaticSpec defines the desired state of DjangoStatic.
-type DjangoStaticSpec struct{}
+type DjangoStaticSpec struct {
+	CommandOptions `json:",inline"`
+}
 
 // DjangoStaticStatus defines the observed state of DjangoStatic.
 type DjangoStaticStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-	Collected metav1.Time `json:"collected"`
+	// Collected is when collectstatic finished successfully.
+	// +optional
+	Collected *metav1.Time `json:"collected,omitempty"`
+
+	CommandStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Collected",type=date,JSONPath=`.status.collected`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DjangoStatic is the Schema for the djangostatics API.
 type DjangoStatic struct {
@@ -51,6 +60,16 @@ type DjangoStaticList struct {
 	Items           []DjangoStatic `json:"items"`
 }
 
+// GetCommandOptions returns the options shared by the one-shot command kinds.
+func (ds *DjangoStatic) GetCommandOptions() *CommandOptions {
+	return &ds.Spec.CommandOptions
+}
+
+// GetCommandStatus returns the status shared by the one-shot command kinds.
+func (ds *DjangoStatic) GetCommandStatus() *CommandStatus {
+	return &ds.Status.CommandStatus
+}
+
 func init() {
 	SchemeBuilder.Register(&DjangoStatic{}, &DjangoStaticList{})
 }
@@ -26,8 +26,39 @@ type DjangoUserSpec struct {
 	Email             string            `json:"email,omitempty"`
 	PasswordSecretRef SecretKeySelector `json:"passwordSecretRef"`
 	Superuser         bool              `json:"superuser"`
+	// Staff sets is_staff, allowing the user to log into the admin site. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Staff *bool `json:"staff,omitempty"`
+	// Active sets is_active. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Active *bool `json:"active,omitempty"`
+	// DeletionPolicy decides what happens to the Django account when this CR is deleted.
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy UserDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	CommandOptions `json:",inline"`
 }
 
+// UserDeletionPolicy decides what happens to a Django account when its DjangoUser is deleted.
+// +kubebuilder:validation:Enum=Retain;Deactivate;Delete
+type UserDeletionPolicy string
+
+const (
+	// UserDeletionRetain leaves the account untouched.
+	UserDeletionRetain UserDeletionPolicy = "Retain"
+	// UserDeletionDeactivate sets is_active to false.
+	UserDeletionDeactivate UserDeletionPolicy = "Deactivate"
+	// UserDeletionDelete deletes the account.
+	UserDeletionDelete UserDeletionPolicy = "Delete"
+)
+
+// UserSkipCleanupAnnotation, set to "true" on a DjangoUser, drops its finalizer without
+// applying the deletion policy, e.g. when its Django app is scaled to zero for good.
+const UserSkipCleanupAnnotation = "django.djangooperator/skip-cleanup"
+
 type SecretKeySelector struct {
 	// Name of the Secret in the same namespace
 	Name string `json:"name"`
@@ -37,13 +68,24 @@ type SecretKeySelector struct {
 
 // DjangoUserStatus defines the observed state of DjangoUser.
 type DjangoUserStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
-	Created metav1.Time `json:"created"`
+	// Created is when the user was created or updated successfully.
+	// +optional
+	Created *metav1.Time `json:"created,omitempty"`
+	// PasswordFingerprint is an HMAC of the password last applied (or being applied),
+	// keyed with a Secret of the operator, so that a rotation of the password is detected.
+	// +optional
+	PasswordFingerprint string `json:"passwordFingerprint,omitempty"`
+
+	CommandStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Created",type=date,JSONPath=`.status.created`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DjangoUser is the Schema for the djangousers API.
 type DjangoUser struct {
@@ -63,6 +105,16 @@ type DjangoUserList struct {
 	Items           []DjangoUser `json:"items"`
 }
 
+// GetCommandOptions returns the options shared by the one-shot command kinds.
+func (du *DjangoUser) GetCommandOptions() *CommandOptions {
+	return &du.Spec.CommandOptions
+}
+
+// GetCommandStatus returns the status shared by the one-shot command kinds.
+func (du *DjangoUser) GetCommandStatus() *CommandStatus {
+	return &du.Status.CommandStatus
+}
+
 func init() {
 	SchemeBuilder.Register(&DjangoUser{}, &DjangoUserList{})
 }
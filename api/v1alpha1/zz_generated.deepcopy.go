@@ -21,10 +21,365 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppCelery) DeepCopyInto(out *AppCelery) {
+	*out = *in
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = make([]CeleryWorker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Beat != nil {
+		in, out := &in.Beat, &out.Beat
+		*out = new(CeleryBeat)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppCelery.
+func (in *AppCelery) DeepCopy() *AppCelery {
+	if in == nil {
+		return nil
+	}
+	out := new(AppCelery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppCondition) DeepCopyInto(out *AppCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppCondition.
+func (in *AppCondition) DeepCopy() *AppCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AppCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppImage) DeepCopyInto(out *AppImage) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppImage.
+func (in *AppImage) DeepCopy() *AppImage {
+	if in == nil {
+		return nil
+	}
+	out := new(AppImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppIngress) DeepCopyInto(out *AppIngress) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]IngressHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppIngress.
+func (in *AppIngress) DeepCopy() *AppIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(AppIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppMigrationState) DeepCopyInto(out *AppMigrationState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppMigrationState.
+func (in *AppMigrationState) DeepCopy() *AppMigrationState {
+	if in == nil {
+		return nil
+	}
+	out := new(AppMigrationState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppMigrations) DeepCopyInto(out *AppMigrations) {
+	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppMigrations.
+func (in *AppMigrations) DeepCopy() *AppMigrations {
+	if in == nil {
+		return nil
+	}
+	out := new(AppMigrations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppProbes) DeepCopyInto(out *AppProbes) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(HTTPProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(HTTPProbe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppProbes.
+func (in *AppProbes) DeepCopy() *AppProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(AppProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMigrate) DeepCopyInto(out *AutoMigrate) {
+	*out = *in
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMigrate.
+func (in *AutoMigrate) DeepCopy() *AutoMigrate {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMigrate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMigrateStatus) DeepCopyInto(out *AutoMigrateStatus) {
+	*out = *in
+	if in.MigratedAt != nil {
+		in, out := &in.MigratedAt, &out.MigratedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMigrateStatus.
+func (in *AutoMigrateStatus) DeepCopy() *AutoMigrateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMigrateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CeleryBeat) DeepCopyInto(out *CeleryBeat) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CeleryBeat.
+func (in *CeleryBeat) DeepCopy() *CeleryBeat {
+	if in == nil {
+		return nil
+	}
+	out := new(CeleryBeat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CeleryWorker) DeepCopyInto(out *CeleryWorker) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CeleryWorker.
+func (in *CeleryWorker) DeepCopy() *CeleryWorker {
+	if in == nil {
+		return nil
+	}
+	out := new(CeleryWorker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandEnvVar) DeepCopyInto(out *CommandEnvVar) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandEnvVar.
+func (in *CommandEnvVar) DeepCopy() *CommandEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandOptions) DeepCopyInto(out *CommandOptions) {
+	*out = *in
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandOptions.
+func (in *CommandOptions) DeepCopy() *CommandOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandOutput) DeepCopyInto(out *CommandOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandOutput.
+func (in *CommandOutput) DeepCopy() *CommandOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandStatus) DeepCopyInto(out *CommandStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(CommandOutput)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandStatus.
+func (in *CommandStatus) DeepCopy() *CommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DjangoApp) DeepCopyInto(out *DjangoApp) {
 	*out = *in
@@ -87,9 +442,51 @@ func (in *DjangoAppList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DjangoAppSpec) DeepCopyInto(out *DjangoAppSpec) {
 	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(AppImage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(AppIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(AppProbes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Celery != nil {
+		in, out := &in.Celery, &out.Celery
+		*out = new(AppCelery)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Values != nil {
 		in, out := &in.Values, &out.Values
-		*out = new(v1.JSON)
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoMigrate != nil {
+		in, out := &in.AutoMigrate, &out.AutoMigrate
+		*out = new(AutoMigrate)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -105,45 +502,437 @@ func (in *DjangoAppSpec) DeepCopy() *DjangoAppSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoAppStatus) DeepCopyInto(out *DjangoAppStatus) {
+func (in *DjangoAppStatus) DeepCopyInto(out *DjangoAppStatus) {
+	*out = *in
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+	if in.Release != nil {
+		in, out := &in.Release, &out.Release
+		*out = new(ReleaseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Django != nil {
+		in, out := &in.Django, &out.Django
+		*out = new(WorkloadStatus)
+		**out = **in
+	}
+	if in.Celery != nil {
+		in, out := &in.Celery, &out.Celery
+		*out = new(WorkloadStatus)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]AppCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoMigrate != nil {
+		in, out := &in.AutoMigrate, &out.AutoMigrate
+		*out = new(AutoMigrateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoAppStatus.
+func (in *DjangoAppStatus) DeepCopy() *DjangoAppStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoAppStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCelery) DeepCopyInto(out *DjangoCelery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCelery.
+func (in *DjangoCelery) DeepCopy() *DjangoCelery {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCelery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCelery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCeleryList) DeepCopyInto(out *DjangoCeleryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DjangoCelery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCeleryList.
+func (in *DjangoCeleryList) DeepCopy() *DjangoCeleryList {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCeleryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCeleryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCelerySpec) DeepCopyInto(out *DjangoCelerySpec) {
+	*out = *in
+	in.CommandOptions.DeepCopyInto(&out.CommandOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCelerySpec.
+func (in *DjangoCelerySpec) DeepCopy() *DjangoCelerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCelerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCeleryStatus) DeepCopyInto(out *DjangoCeleryStatus) {
+	*out = *in
+	if in.Executed != nil {
+		in, out := &in.Executed, &out.Executed
+		*out = (*in).DeepCopy()
+	}
+	in.CommandStatus.DeepCopyInto(&out.CommandStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCeleryStatus.
+func (in *DjangoCeleryStatus) DeepCopy() *DjangoCeleryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCeleryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCommand) DeepCopyInto(out *DjangoCommand) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCommand.
+func (in *DjangoCommand) DeepCopy() *DjangoCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCommand) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCommandList) DeepCopyInto(out *DjangoCommandList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DjangoCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCommandList.
+func (in *DjangoCommandList) DeepCopy() *DjangoCommandList {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCommandList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCommandList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCommandSpec) DeepCopyInto(out *DjangoCommandSpec) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]CommandEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	in.CommandOptions.DeepCopyInto(&out.CommandOptions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCommandSpec.
+func (in *DjangoCommandSpec) DeepCopy() *DjangoCommandSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCommandSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCommandStatus) DeepCopyInto(out *DjangoCommandStatus) {
+	*out = *in
+	if in.Executed != nil {
+		in, out := &in.Executed, &out.Executed
+		*out = (*in).DeepCopy()
+	}
+	in.CommandStatus.DeepCopyInto(&out.CommandStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCommandStatus.
+func (in *DjangoCommandStatus) DeepCopy() *DjangoCommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCronJob) DeepCopyInto(out *DjangoCronJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCronJob.
+func (in *DjangoCronJob) DeepCopy() *DjangoCronJob {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCronJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCronJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCronJobList) DeepCopyInto(out *DjangoCronJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DjangoCronJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCronJobList.
+func (in *DjangoCronJobList) DeepCopy() *DjangoCronJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCronJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjangoCronJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCronJobSpec) DeepCopyInto(out *DjangoCronJobSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCronJobSpec.
+func (in *DjangoCronJobSpec) DeepCopy() *DjangoCronJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCronJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCronJobStatus) DeepCopyInto(out *DjangoCronJobStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCronJobStatus.
+func (in *DjangoCronJobStatus) DeepCopy() *DjangoCronJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoCronJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoCronJobTemplate) DeepCopyInto(out *DjangoCronJobTemplate) {
 	*out = *in
-	if in.Created != nil {
-		in, out := &in.Created, &out.Created
-		*out = (*in).DeepCopy()
+	if in.Migrate != nil {
+		in, out := &in.Migrate, &out.Migrate
+		*out = new(DjangoMigrateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(DjangoStaticSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Celery != nil {
+		in, out := &in.Celery, &out.Celery
+		*out = new(DjangoCelerySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = new(DjangoCommandSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoAppStatus.
-func (in *DjangoAppStatus) DeepCopy() *DjangoAppStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCronJobTemplate.
+func (in *DjangoCronJobTemplate) DeepCopy() *DjangoCronJobTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoAppStatus)
+	out := new(DjangoCronJobTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoCelery) DeepCopyInto(out *DjangoCelery) {
+func (in *DjangoMigrate) DeepCopyInto(out *DjangoMigrate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCelery.
-func (in *DjangoCelery) DeepCopy() *DjangoCelery {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrate.
+func (in *DjangoMigrate) DeepCopy() *DjangoMigrate {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoCelery)
+	out := new(DjangoMigrate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DjangoCelery) DeepCopyObject() runtime.Object {
+func (in *DjangoMigrate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -151,31 +940,31 @@ func (in *DjangoCelery) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoCeleryList) DeepCopyInto(out *DjangoCeleryList) {
+func (in *DjangoMigrateList) DeepCopyInto(out *DjangoMigrateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DjangoCelery, len(*in))
+		*out = make([]DjangoMigrate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCeleryList.
-func (in *DjangoCeleryList) DeepCopy() *DjangoCeleryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateList.
+func (in *DjangoMigrateList) DeepCopy() *DjangoMigrateList {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoCeleryList)
+	out := new(DjangoMigrateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DjangoCeleryList) DeepCopyObject() runtime.Object {
+func (in *DjangoMigrateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -183,57 +972,72 @@ func (in *DjangoCeleryList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoCelerySpec) DeepCopyInto(out *DjangoCelerySpec) {
+func (in *DjangoMigrateSpec) DeepCopyInto(out *DjangoMigrateSpec) {
 	*out = *in
+	in.CommandOptions.DeepCopyInto(&out.CommandOptions)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCelerySpec.
-func (in *DjangoCelerySpec) DeepCopy() *DjangoCelerySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateSpec.
+func (in *DjangoMigrateSpec) DeepCopy() *DjangoMigrateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoCelerySpec)
+	out := new(DjangoMigrateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoCeleryStatus) DeepCopyInto(out *DjangoCeleryStatus) {
+func (in *DjangoMigrateStatus) DeepCopyInto(out *DjangoMigrateStatus) {
 	*out = *in
-	in.Executed.DeepCopyInto(&out.Executed)
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = (*in).DeepCopy()
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(MigrationPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreApply != nil {
+		in, out := &in.PreApply, &out.PreApply
+		*out = new(MigrationState)
+		(*in).DeepCopyInto(*out)
+	}
+	in.CommandStatus.DeepCopyInto(&out.CommandStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoCeleryStatus.
-func (in *DjangoCeleryStatus) DeepCopy() *DjangoCeleryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateStatus.
+func (in *DjangoMigrateStatus) DeepCopy() *DjangoMigrateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoCeleryStatus)
+	out := new(DjangoMigrateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoMigrate) DeepCopyInto(out *DjangoMigrate) {
+func (in *DjangoPipeline) DeepCopyInto(out *DjangoPipeline) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrate.
-func (in *DjangoMigrate) DeepCopy() *DjangoMigrate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoPipeline.
+func (in *DjangoPipeline) DeepCopy() *DjangoPipeline {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoMigrate)
+	out := new(DjangoPipeline)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DjangoMigrate) DeepCopyObject() runtime.Object {
+func (in *DjangoPipeline) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -241,31 +1045,31 @@ func (in *DjangoMigrate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoMigrateList) DeepCopyInto(out *DjangoMigrateList) {
+func (in *DjangoPipelineList) DeepCopyInto(out *DjangoPipelineList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DjangoMigrate, len(*in))
+		*out = make([]DjangoPipeline, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateList.
-func (in *DjangoMigrateList) DeepCopy() *DjangoMigrateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoPipelineList.
+func (in *DjangoPipelineList) DeepCopy() *DjangoPipelineList {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoMigrateList)
+	out := new(DjangoPipelineList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DjangoMigrateList) DeepCopyObject() runtime.Object {
+func (in *DjangoPipelineList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -273,32 +1077,96 @@ func (in *DjangoMigrateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoMigrateSpec) DeepCopyInto(out *DjangoMigrateSpec) {
+func (in *DjangoPipelineSpec) DeepCopyInto(out *DjangoPipelineSpec) {
 	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DjangoPipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateSpec.
-func (in *DjangoMigrateSpec) DeepCopy() *DjangoMigrateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoPipelineSpec.
+func (in *DjangoPipelineSpec) DeepCopy() *DjangoPipelineSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoMigrateSpec)
+	out := new(DjangoPipelineSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjangoMigrateStatus) DeepCopyInto(out *DjangoMigrateStatus) {
+func (in *DjangoPipelineStatus) DeepCopyInto(out *DjangoPipelineStatus) {
 	*out = *in
-	in.Applied.DeepCopyInto(&out.Applied)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoMigrateStatus.
-func (in *DjangoMigrateStatus) DeepCopy() *DjangoMigrateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoPipelineStatus.
+func (in *DjangoPipelineStatus) DeepCopy() *DjangoPipelineStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DjangoMigrateStatus)
+	out := new(DjangoPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjangoPipelineStep) DeepCopyInto(out *DjangoPipelineStep) {
+	*out = *in
+	if in.Migrate != nil {
+		in, out := &in.Migrate, &out.Migrate
+		*out = new(DjangoMigrateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(DjangoStaticSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Celery != nil {
+		in, out := &in.Celery, &out.Celery
+		*out = new(DjangoCelerySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.User != nil {
+		in, out := &in.User, &out.User
+		*out = new(DjangoUserSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = new(DjangoCommandSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoPipelineStep.
+func (in *DjangoPipelineStep) DeepCopy() *DjangoPipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(DjangoPipelineStep)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -308,7 +1176,7 @@ func (in *DjangoStatic) DeepCopyInto(out *DjangoStatic) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -365,6 +1233,7 @@ func (in *DjangoStaticList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DjangoStaticSpec) DeepCopyInto(out *DjangoStaticSpec) {
 	*out = *in
+	in.CommandOptions.DeepCopyInto(&out.CommandOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoStaticSpec.
@@ -380,7 +1249,11 @@ func (in *DjangoStaticSpec) DeepCopy() *DjangoStaticSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DjangoStaticStatus) DeepCopyInto(out *DjangoStaticStatus) {
 	*out = *in
-	in.Collected.DeepCopyInto(&out.Collected)
+	if in.Collected != nil {
+		in, out := &in.Collected, &out.Collected
+		*out = (*in).DeepCopy()
+	}
+	in.CommandStatus.DeepCopyInto(&out.CommandStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoStaticStatus.
@@ -398,7 +1271,7 @@ func (in *DjangoUser) DeepCopyInto(out *DjangoUser) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -456,6 +1329,17 @@ func (in *DjangoUserList) DeepCopyObject() runtime.Object {
 func (in *DjangoUserSpec) DeepCopyInto(out *DjangoUserSpec) {
 	*out = *in
 	out.PasswordSecretRef = in.PasswordSecretRef
+	if in.Staff != nil {
+		in, out := &in.Staff, &out.Staff
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	in.CommandOptions.DeepCopyInto(&out.CommandOptions)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoUserSpec.
@@ -471,7 +1355,11 @@ func (in *DjangoUserSpec) DeepCopy() *DjangoUserSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DjangoUserStatus) DeepCopyInto(out *DjangoUserStatus) {
 	*out = *in
-	in.Created.DeepCopyInto(&out.Created)
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+	in.CommandStatus.DeepCopyInto(&out.CommandStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjangoUserStatus.
@@ -484,6 +1372,222 @@ func (in *DjangoUserStatus) DeepCopy() *DjangoUserStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbe.
+func (in *HTTPProbe) DeepCopy() *HTTPProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressHost) DeepCopyInto(out *IngressHost) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressHost.
+func (in *IngressHost) DeepCopy() *IngressHost {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTLS) DeepCopyInto(out *IngressTLS) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTLS.
+func (in *IngressTLS) DeepCopy() *IngressTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlan) DeepCopyInto(out *MigrationPlan) {
+	*out = *in
+	in.PlannedAt.DeepCopyInto(&out.PlannedAt)
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]AppMigrations, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlan.
+func (in *MigrationPlan) DeepCopy() *MigrationPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationState) DeepCopyInto(out *MigrationState) {
+	*out = *in
+	in.RecordedAt.DeepCopyInto(&out.RecordedAt)
+	if in.Apps != nil {
+		in, out := &in.Apps, &out.Apps
+		*out = make([]AppMigrationState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationState.
+func (in *MigrationState) DeepCopy() *MigrationState {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStepStatus) DeepCopyInto(out *PipelineStepStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStepStatus.
+func (in *PipelineStepStatus) DeepCopy() *PipelineStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseStatus) DeepCopyInto(out *ReleaseStatus) {
+	*out = *in
+	if in.LastDeployed != nil {
+		in, out := &in.LastDeployed, &out.LastDeployed
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseStatus.
+func (in *ReleaseStatus) DeepCopy() *ReleaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+	if in.SuccessfulHistoryLimit != nil {
+		in, out := &in.SuccessfulHistoryLimit, &out.SuccessfulHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedHistoryLimit != nil {
+		in, out := &in.FailedHistoryLimit, &out.FailedHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.BackoffBase != nil {
+		in, out := &in.BackoffBase, &out.BackoffBase
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BackoffCap != nil {
+		in, out := &in.BackoffCap, &out.BackoffCap
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
@@ -498,3 +1602,18 @@ func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
+func (in *WorkloadStatus) DeepCopy() *WorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -43,6 +45,7 @@ import (
 
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
 	"github.com/jvdiago/django-operator/internal/controller"
+	webhookdjangov1alpha1 "github.com/jvdiago/django-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -84,20 +87,75 @@ func getDjangoPodLabel(podLabelEnvVar string) controller.PodLabel {
 	}
 }
 
-func getKeepCRs(keepCRsEnvVar string) int {
+func getKeepCRs(keepCRsEnvVar string) *int32 {
 	// Look up the raw string (if unset, keepCrsEnv == "")
 	keepCrsEnv, found := os.LookupEnv(keepCRsEnvVar)
-	if !found {
-		return 0
+	if !found || keepCrsEnv == "" {
+		return nil
 	}
 
 	// Parse it
-	keep, err := strconv.Atoi(keepCrsEnv)
+	keep, err := strconv.ParseInt(keepCrsEnv, 10, 32)
+	if err != nil || keep < 0 {
+		setupLog.Info("warning: not a valid non-negative integer, ignoring it", "env", keepCRsEnvVar, "value", keepCrsEnv)
+		return nil
+	}
+	return ptr.To(int32(keep))
+}
+
+// getRetentionPolicy reads the retention policy of a kind. NUM_OLD_CRS, NUM_FAILED_CRS and
+// CR_TTL_SECONDS_AFTER_FINISHED apply to every kind, and can be overridden per kind by
+// prefixing them with the upper-cased kind, e.g. DJANGOMIGRATE_NUM_OLD_CRS.
+func getRetentionPolicy(kind string) djangov1alpha1.RetentionPolicy {
+	get := func(envVar string) *int32 {
+		if v := getKeepCRs(strings.ToUpper(kind) + "_" + envVar); v != nil {
+			return v
+		}
+		return getKeepCRs(envVar)
+	}
+	return djangov1alpha1.RetentionPolicy{
+		SuccessfulHistoryLimit:  get("NUM_OLD_CRS"),
+		FailedHistoryLimit:      get("NUM_FAILED_CRS"),
+		TTLSecondsAfterFinished: get("CR_TTL_SECONDS_AFTER_FINISHED"),
+	}
+}
+
+// getList parses a comma-separated list, ignoring empty items.
+func getList(listEnvVar string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(listEnvVar), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getDuration(durationEnvVar string) time.Duration {
+	raw, found := os.LookupEnv(durationEnvVar)
+	if !found || raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
 	if err != nil {
-		setupLog.Info("warning: %s is not a valid integer, defaulting to 0", keepCRsEnvVar)
+		setupLog.Info("warning: not a valid duration, using the default", "env", durationEnvVar, "value", raw)
 		return 0
 	}
-	return keep
+	return d
+}
+
+func getRunnerType(runnerEnvVar string) djangov1alpha1.RunnerType {
+	runner, found := os.LookupEnv(runnerEnvVar)
+	if !found || runner == "" {
+		return djangov1alpha1.RunnerExec
+	}
+	switch t := djangov1alpha1.RunnerType(runner); t {
+	case djangov1alpha1.RunnerExec, djangov1alpha1.RunnerJob:
+		return t
+	default:
+		setupLog.Info("warning: invalid runner, defaulting to Exec", "env", runnerEnvVar, "value", runner)
+		return djangov1alpha1.RunnerExec
+	}
 }
 
 // nolint:gocyclo
@@ -274,14 +332,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	keepCRsEnvVar := "NUM_OLD_CRS"
-	keepCrs := getKeepCRs(keepCRsEnvVar)
+	runnerEnvVar := "DJANGO_RUNNER"
+	runner := getRunnerType(runnerEnvVar)
+
+	// Containers to run commands in; empty means the first container of the pod
+	djangoContainer := os.Getenv("DJANGO_CONTAINER_NAME")
+	celeryContainer := os.Getenv("CELERY_CONTAINER_NAME")
 
 	if err = (&controller.DjangoUserReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		DjangoPodlabel: djangoPodLabel,
-		KeepCRs:        keepCrs,
+		Runner:         runner,
+		Container:      djangoContainer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DjangoUser")
 		os.Exit(1)
@@ -290,7 +353,8 @@ func main() {
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		DjangoPodlabel: djangoPodLabel,
-		KeepCRs:        keepCrs,
+		Runner:         runner,
+		Container:      djangoContainer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DjangoMigrate")
 		os.Exit(1)
@@ -299,7 +363,8 @@ func main() {
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		DjangoPodlabel: djangoPodLabel,
-		KeepCRs:        keepCrs,
+		Runner:         runner,
+		Container:      djangoContainer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DjangoStatic")
 		os.Exit(1)
@@ -308,11 +373,79 @@ func main() {
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
 		DjangoPodlabel: celeryPodLabel,
-		KeepCRs:        keepCrs,
+		Runner:         runner,
+		Container:      celeryContainer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DjangoCelery")
 		os.Exit(1)
 	}
+	commandAllowlist := getList("DJANGO_COMMAND_ALLOWLIST")
+	if len(commandAllowlist) == 0 {
+		setupLog.Info("DJANGO_COMMAND_ALLOWLIST is empty, every DjangoCommand will be refused; " +
+			"list the allowed management commands, or * for all of them")
+	}
+	if err = (&controller.DjangoCommandReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		DjangoPodlabel: djangoPodLabel,
+		Runner:         runner,
+		Container:      djangoContainer,
+		Allowed:        commandAllowlist,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DjangoCommand")
+		os.Exit(1)
+	}
+	if err = (&controller.DjangoCronJobReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DjangoCronJob")
+		os.Exit(1)
+	}
+	if err = (&controller.DjangoPipelineReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DjangoPipeline")
+		os.Exit(1)
+	}
+	if err = (&controller.AutoMigrateReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		DjangoPodlabel: djangoPodLabel,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AutoMigrate")
+		os.Exit(1)
+	}
+	if err = (&controller.AppStatusReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AppStatus")
+		os.Exit(1)
+	}
+	if err = (&controller.RetentionController{
+		Client:    mgr.GetClient(),
+		Namespace: watchNamespace,
+		Interval:  getDuration("CR_RETENTION_INTERVAL"),
+		Policies: map[string]djangov1alpha1.RetentionPolicy{
+			"DjangoUser":    getRetentionPolicy("DjangoUser"),
+			"DjangoMigrate": getRetentionPolicy("DjangoMigrate"),
+			"DjangoStatic":  getRetentionPolicy("DjangoStatic"),
+			"DjangoCelery":  getRetentionPolicy("DjangoCelery"),
+			"DjangoCommand": getRetentionPolicy("DjangoCommand"),
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create retention controller")
+		os.Exit(1)
+	}
+	// The webhook is opt-in: config/default sets ENABLE_WEBHOOKS when it is enabled there
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = webhookdjangov1alpha1.SetupDjangoAppWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DjangoApp")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 	if err := controller.SetupHelmController(mgr); err != nil {
 		setupLog.Error(err, "unable to start Helm controller")
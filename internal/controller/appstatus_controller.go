@@ -0,0 +1,292 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+const (
+	// componentLabel is set by the chart on every workload to the part of the release it runs.
+	componentLabel = "app.kubernetes.io/component"
+	// djangoComponent is the component of the Django server workloads.
+	djangoComponent = "django-server"
+	// celeryBeatComponent is the component of the Celery beat workload; Celery workers
+	// are <release>-celery-work-<queue>.
+	celeryBeatComponent = "celery-beat"
+
+	appPartDjango = "Django"
+	appPartCelery = "Celery"
+
+	ReasonReplicasReady    = "ReplicasReady"
+	ReasonReplicasNotReady = "ReplicasNotReady"
+	ReasonNoWorkloads      = "NoWorkloads"
+)
+
+// ReleaseGetter returns the deployed Helm release of a DjangoApp, or nil if there is none.
+type ReleaseGetter interface {
+	Deployed(ctx context.Context, namespace, name string) (*release.Release, error)
+}
+
+// SecretReleases reads releases from the Secrets the Helm reconciler stores them in.
+type SecretReleases struct {
+	Clientset kubernetes.Interface
+}
+
+// Deployed returns the latest deployed revision of the release name.
+func (s SecretReleases) Deployed(_ context.Context, namespace, name string) (*release.Release, error) {
+	rel, err := storage.Init(driver.NewSecrets(s.Clientset.CoreV1().Secrets(namespace))).Deployed(name)
+	if errors.Is(err, driver.ErrNoDeployedReleases) || errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	return rel, err
+}
+
+// AppStatusReconciler reports the Helm release and the health of the workloads of a
+// DjangoApp in its status. The Helm reconciler rewrites the status whenever its
+// conditions change, keeping these fields through helmStatusClient.
+type AppStatusReconciler struct {
+	client.Client
+	Releases ReleaseGetter
+}
+
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangoapps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangoapps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *AppStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	var app djangov1alpha1.DjangoApp
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !app.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	before := app.DeepCopy()
+
+	rel, err := r.Releases.Deployed(ctx, app.Namespace, app.Name)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting release of %s: %w", app.Name, err)
+	}
+	app.Status.Release = nil
+	if rel != nil {
+		if app.Status.Release, err = releaseStatus(rel); err != nil {
+			return ctrl.Result{}, err
+		}
+		if rel.Info != nil && !rel.Info.FirstDeployed.IsZero() {
+			app.Status.Created = ptr.To(metav1.NewTime(rel.Info.FirstDeployed.Time).Rfc3339Copy())
+		}
+	}
+
+	django, celery, err := r.workloads(ctx, &app)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	app.Status.Django, app.Status.Celery = django, celery
+	setAvailable(&app.Status, django, celery)
+
+	if equality.Semantic.DeepEqual(before.Status, app.Status) {
+		return ctrl.Result{}, nil
+	}
+	// The Helm reconciler writes the status too, and a merge patch replaces the conditions
+	// list whole. The lock keeps a stale copy of the conditions from undoing the Helm
+	// reconciler's latest update: when it wrote in between, the patch fails with a
+	// conflict, which is expected, and the app is reconciled again from a fresh copy.
+	if err := r.Status().Patch(ctx, &app, client.MergeFromWithOptions(before, client.MergeFromWithOptimisticLock{})); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	logger.V(1).Info("Updated DjangoApp status", "name", app.Name)
+	return ctrl.Result{}, nil
+}
+
+// workloads sums the replicas of the Django and Celery Deployments and StatefulSets of
+// the release of app. A part without workloads is reported as nil.
+func (r *AppStatusReconciler) workloads(ctx context.Context, app *djangov1alpha1.DjangoApp) (
+	*djangov1alpha1.WorkloadStatus, *djangov1alpha1.WorkloadStatus, error,
+) {
+	opts := []client.ListOption{client.InNamespace(app.Namespace), client.MatchingLabels{helmInstanceLabel: app.Name}}
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, opts...); err != nil {
+		return nil, nil, err
+	}
+	var statefulSets appsv1.StatefulSetList
+	if err := r.List(ctx, &statefulSets, opts...); err != nil {
+		return nil, nil, err
+	}
+
+	var django, celery *djangov1alpha1.WorkloadStatus
+	add := func(labels map[string]string, desired *int32, ready, updated int32) {
+		var ws **djangov1alpha1.WorkloadStatus
+		switch appPart(labels, app.Name) {
+		case appPartDjango:
+			ws = &django
+		case appPartCelery:
+			ws = &celery
+		default:
+			return
+		}
+		if *ws == nil {
+			*ws = &djangov1alpha1.WorkloadStatus{}
+		}
+		(*ws).Replicas += ptr.Deref(desired, 1)
+		(*ws).ReadyReplicas += ready
+		(*ws).UpdatedReplicas += updated
+	}
+	for _, d := range deployments.Items {
+		add(d.Labels, d.Spec.Replicas, d.Status.ReadyReplicas, d.Status.UpdatedReplicas)
+	}
+	for _, s := range statefulSets.Items {
+		add(s.Labels, s.Spec.Replicas, s.Status.ReadyReplicas, s.Status.UpdatedReplicas)
+	}
+	return django, celery, nil
+}
+
+// appPart returns the part of release, Django or Celery, a workload with labels runs, or
+// "" for the others, e.g. flower.
+func appPart(labels map[string]string, release string) string {
+	c := labels[componentLabel]
+	switch {
+	case c == djangoComponent:
+		return appPartDjango
+	case c == celeryBeatComponent, strings.HasPrefix(c, release+"-celery-work-"):
+		return appPartCelery
+	}
+	return ""
+}
+
+// releaseStatus summarises rel.
+func releaseStatus(rel *release.Release) (*djangov1alpha1.ReleaseStatus, error) {
+	st := &djangov1alpha1.ReleaseStatus{Name: rel.Name, Revision: rel.Version}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		st.ChartVersion = rel.Chart.Metadata.Version
+	}
+	if rel.Info != nil && !rel.Info.LastDeployed.IsZero() {
+		// Truncated like the API server does, so an unchanged release is not patched again
+		st.LastDeployed = ptr.To(metav1.NewTime(rel.Info.LastDeployed.Time).Rfc3339Copy())
+	}
+	hash, err := valuesHash(rel.Config)
+	if err != nil {
+		return nil, err
+	}
+	st.ValuesHash = hash
+	return st, nil
+}
+
+// valuesHash returns the SHA-256 of vals. Maps are marshalled with sorted keys, so equal
+// values always hash the same.
+func valuesHash(vals map[string]interface{}) (string, error) {
+	if vals == nil {
+		vals = map[string]interface{}{}
+	}
+	raw, err := json.Marshal(vals)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// setAvailable sets the Available condition of st: True when there are Django
+// workloads and every Django and Celery replica is ready.
+func setAvailable(st *djangov1alpha1.DjangoAppStatus, django, celery *djangov1alpha1.WorkloadStatus) {
+	cond := djangov1alpha1.AppCondition{
+		Type:    djangov1alpha1.AppConditionAvailable,
+		Status:  corev1.ConditionTrue,
+		Reason:  ReasonReplicasReady,
+		Message: "all replicas are ready",
+	}
+	var notReady []string
+	for part, ws := range []*djangov1alpha1.WorkloadStatus{django, celery} {
+		if ws != nil && ws.ReadyReplicas < ws.Replicas {
+			name := []string{appPartDjango, appPartCelery}[part]
+			notReady = append(notReady, fmt.Sprintf("%s %d/%d", name, ws.ReadyReplicas, ws.Replicas))
+		}
+	}
+	switch {
+	case django == nil:
+		cond.Status, cond.Reason, cond.Message = corev1.ConditionFalse, ReasonNoWorkloads, "no Django workload found"
+	case len(notReady) > 0:
+		cond.Status, cond.Reason = corev1.ConditionFalse, ReasonReplicasNotReady
+		cond.Message = "replicas not ready: " + strings.Join(notReady, ", ")
+	}
+	setAppCondition(&st.Conditions, cond)
+}
+
+// setAppCondition sets cond in conds, keeping its transition time when its status
+// does not change.
+func setAppCondition(conds *[]djangov1alpha1.AppCondition, cond djangov1alpha1.AppCondition) {
+	for i := range *conds {
+		existing := &(*conds)[i]
+		if existing.Type != cond.Type {
+			continue
+		}
+		cond.LastTransitionTime = existing.LastTransitionTime
+		if existing.Status != cond.Status {
+			cond.LastTransitionTime = metav1.Now()
+		}
+		*existing = cond
+		return
+	}
+	cond.LastTransitionTime = metav1.Now()
+	*conds = append(*conds, cond)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AppStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Releases == nil {
+		cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+		r.Releases = SecretReleases{Clientset: cs}
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&djangov1alpha1.DjangoApp{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
+		// The Helm reconciler stores every revision in a Secret owned by the DjangoApp
+		Owns(&corev1.Secret{}).
+		Named("appstatus").
+		Complete(r)
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// fakeReleases is a ReleaseGetter returning rel.
+type fakeReleases struct {
+	rel *release.Release
+}
+
+func (f fakeReleases) Deployed(context.Context, string, string) (*release.Release, error) {
+	return f.rel, nil
+}
+
+// appCondition returns the condition of type t of app, or nil.
+func appCondition(app *djangov1alpha1.DjangoApp, t string) *djangov1alpha1.AppCondition {
+	for i := range app.Status.Conditions {
+		if app.Status.Conditions[i].Type == t {
+			return &app.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+var _ = Describe("AppStatus Controller", func() {
+	Context("When summarising a release", func() {
+		It("should hash equal values the same", func() {
+			a, err := valuesHash(map[string]interface{}{"image": map[string]interface{}{"tag": "v1", "repository": "shop"}})
+			Expect(err).NotTo(HaveOccurred())
+			b, err := valuesHash(map[string]interface{}{"image": map[string]interface{}{"repository": "shop", "tag": "v1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a).To(Equal(b))
+			Expect(a).To(HavePrefix("sha256:"))
+			c, err := valuesHash(map[string]interface{}{"image": map[string]interface{}{"repository": "shop", "tag": "v2"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(Equal(a))
+		})
+
+		It("should tell Django from Celery workloads", func() {
+			Expect(appPart(map[string]string{componentLabel: "django-server"}, "shop")).To(Equal(appPartDjango))
+			Expect(appPart(map[string]string{componentLabel: "shop-celery-work-default"}, "shop")).To(Equal(appPartCelery))
+			Expect(appPart(map[string]string{componentLabel: "celery-beat"}, "shop")).To(Equal(appPartCelery))
+			Expect(appPart(map[string]string{componentLabel: "celery-flower"}, "shop")).To(BeEmpty())
+			Expect(appPart(map[string]string{componentLabel: "other-celery-work-default"}, "shop")).To(BeEmpty())
+		})
+
+		It("should only be available once every replica is ready", func() {
+			st := &djangov1alpha1.DjangoAppStatus{}
+			setAvailable(st, nil, nil)
+			Expect(st.Conditions).To(HaveLen(1))
+			Expect(st.Conditions[0].Reason).To(Equal(ReasonNoWorkloads))
+
+			setAvailable(st, &djangov1alpha1.WorkloadStatus{Replicas: 2, ReadyReplicas: 1},
+				&djangov1alpha1.WorkloadStatus{Replicas: 1, ReadyReplicas: 0})
+			Expect(st.Conditions[0].Status).To(Equal(corev1.ConditionFalse))
+			Expect(st.Conditions[0].Message).To(Equal("replicas not ready: Django 1/2, Celery 0/1"))
+			transition := metav1.NewTime(time.Now().Add(-time.Hour))
+			st.Conditions[0].LastTransitionTime = transition
+
+			setAvailable(st, &djangov1alpha1.WorkloadStatus{Replicas: 2, ReadyReplicas: 1}, nil)
+			Expect(st.Conditions[0].LastTransitionTime).To(Equal(transition))
+			setAvailable(st, &djangov1alpha1.WorkloadStatus{Replicas: 2, ReadyReplicas: 2}, nil)
+			Expect(st.Conditions).To(HaveLen(1))
+			Expect(st.Conditions[0].Status).To(Equal(corev1.ConditionTrue))
+			Expect(st.Conditions[0].LastTransitionTime).NotTo(Equal(transition))
+		})
+	})
+
+	Context("When reporting the status of a DjangoApp", func() {
+		ctx := context.Background()
+		appName := types.NamespacedName{Name: "status-app", Namespace: "default"}
+		deployment := func(name, component string, replicas int32) *appsv1.Deployment {
+			labels := map[string]string{helmInstanceLabel: appName.Name, componentLabel: component}
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: appName.Namespace, Labels: labels},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To(replicas),
+					Selector: &metav1.LabelSelector{MatchLabels: labels},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "shop:v1"}}},
+					},
+				},
+			}
+		}
+		django := deployment("status-app-django", "django-server", 2)
+		celery := deployment("status-app-celery-work-default", "status-app-celery-work-default", 1)
+		setReady := func(d *appsv1.Deployment, ready int32) {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(d), d)).To(Succeed())
+			d.Status.Replicas, d.Status.ReadyReplicas, d.Status.UpdatedReplicas = *d.Spec.Replicas, ready, *d.Spec.Replicas
+			Expect(k8sClient.Status().Update(ctx, d)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, django.DeepCopy())).To(Succeed())
+			Expect(k8sClient.Create(ctx, celery.DeepCopy())).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, django)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, celery)).To(Succeed())
+		})
+
+		It("should report the release, the replicas and availability", func() {
+			deployed := helmtime.Now()
+			controllerReconciler := &AppStatusReconciler{
+				Client: k8sClient,
+				Releases: fakeReleases{rel: &release.Release{
+					Name:    appName.Name,
+					Version: 3,
+					Chart:   &chart.Chart{Metadata: &chart.Metadata{Version: "1.1.0"}},
+					Config:  map[string]interface{}{"image": map[string]interface{}{"tag": "v1"}},
+					Info:    &release.Info{FirstDeployed: deployed, LastDeployed: deployed},
+				}},
+			}
+			app := &djangov1alpha1.DjangoApp{}
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			// As written by the Helm reconciler, without a reason
+			app.Status.Conditions = []djangov1alpha1.AppCondition{{
+				Type: djangov1alpha1.AppConditionDeployed, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Now(),
+			}}
+			Expect(k8sClient.Status().Update(ctx, app)).To(Succeed())
+			setReady(django, 1)
+			setReady(celery, 1)
+
+			By("reporting the replicas that are not ready yet")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(app.Status.Release).NotTo(BeNil())
+			Expect(app.Status.Release.Revision).To(Equal(3))
+			Expect(app.Status.Release.ChartVersion).To(Equal("1.1.0"))
+			Expect(app.Status.Release.ValuesHash).To(HavePrefix("sha256:"))
+			Expect(app.Status.Created).NotTo(BeNil())
+			Expect(app.Status.Django).To(Equal(&djangov1alpha1.WorkloadStatus{Replicas: 2, ReadyReplicas: 1, UpdatedReplicas: 2}))
+			Expect(app.Status.Celery).To(Equal(&djangov1alpha1.WorkloadStatus{Replicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1}))
+			Expect(appCondition(app, djangov1alpha1.AppConditionDeployed)).NotTo(BeNil())
+			Expect(appCondition(app, djangov1alpha1.AppConditionAvailable).Status).To(Equal(corev1.ConditionFalse))
+
+			By("becoming available once every replica is ready")
+			setReady(django, 2)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(appCondition(app, djangov1alpha1.AppConditionAvailable).Status).To(Equal(corev1.ConditionTrue))
+			Expect(appCondition(app, djangov1alpha1.AppConditionDeployed).Status).To(Equal(corev1.ConditionTrue))
+		})
+
+		It("should keep its fields when the Helm reconciler writes the status", func() {
+			controllerReconciler := &AppStatusReconciler{
+				Client:   k8sClient,
+				Releases: fakeReleases{rel: &release.Release{Name: appName.Name, Version: 1}},
+			}
+			setReady(django, 2)
+			setReady(celery, 1)
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			app := &djangov1alpha1.DjangoApp{}
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(app.Status.Release).NotTo(BeNil())
+
+			By("replacing the status with the conditions only, as the Helm reconciler does")
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+			Expect(err).NotTo(HaveOccurred())
+			u := &unstructured.Unstructured{Object: obj}
+			u.SetGroupVersionKind(djangov1alpha1.GroupVersion.WithKind("DjangoApp"))
+			conditions, _, err := unstructured.NestedSlice(obj, "status", "conditions")
+			Expect(err).NotTo(HaveOccurred())
+			u.Object["status"] = map[string]interface{}{"conditions": conditions}
+			Expect(helmStatusClient{Client: k8sClient}.Status().Update(ctx, u)).To(Succeed())
+
+			updated := &djangov1alpha1.DjangoApp{}
+			Expect(k8sClient.Get(ctx, appName, updated)).To(Succeed())
+			Expect(updated.Status.Release).To(Equal(app.Status.Release))
+			Expect(updated.Status.Django).To(Equal(app.Status.Django))
+			Expect(updated.Status.Celery).To(Equal(app.Status.Celery))
+			Expect(appCondition(updated, djangov1alpha1.AppConditionAvailable)).NotTo(BeNil())
+
+			By("finding nothing to put back afterwards")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(app.ResourceVersion).To(Equal(updated.ResourceVersion))
+		})
+	})
+})
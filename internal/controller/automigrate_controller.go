@@ -0,0 +1,382 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	charts "github.com/jvdiago/django-helm-template"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+const (
+	// autoMigrateLabel is set on every automatic run to the name of its DjangoApp.
+	autoMigrateLabel = "django.djangooperator/auto-migrate"
+	// imageAnnotation records the image an automatic run migrates for.
+	imageAnnotation = "django.djangooperator/image"
+	// autoMigrateHistoryLimit is how many finished automatic migrations of previous
+	// images are kept. They are not subject to the retention policy, as the last one
+	// tells which image to hold a PreUpgrade release on.
+	autoMigrateHistoryLimit = 3
+)
+
+// AutoMigrateReconciler creates a DjangoMigrate, and optionally a DjangoStatic, for every
+// image a DjangoApp with spec.autoMigrate deploys.
+type AutoMigrateReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Pods           PodRunner
+	DjangoPodlabel PodLabel
+	Recorder       record.EventRecorder
+	// Defaults are the values of the chart, used when a DjangoApp does not set its image
+	Defaults chartutil.Values
+}
+
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangoapps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangoapps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangomigrates;djangostatics,verbs=get;list;watch;create;delete
+
+func (r *AutoMigrateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	var app djangov1alpha1.DjangoApp
+	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
+		// Runs of a deleted DjangoApp are garbage collected through their owner reference
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if app.Spec.AutoMigrate == nil || !app.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	image, err := appImage(&app, r.Defaults)
+	if err != nil || image == "" {
+		return ctrl.Result{}, err
+	}
+	runs, err := listAutoRuns(ctx, r.Client, &app, image)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.trimHistory(ctx, &app, runs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var result ctrl.Result
+	switch {
+	case runs.migrate == nil:
+		if app.Spec.AutoMigrate.Order != djangov1alpha1.MigrationOrderPreUpgrade {
+			rolledOut, err := r.rolledOut(ctx, app.Namespace, image)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !rolledOut {
+				logger.Info("waiting for the new image to roll out", "image", image)
+				result.RequeueAfter = 10 * time.Second
+				break
+			}
+		}
+		runs.migrate = &djangov1alpha1.DjangoMigrate{
+			ObjectMeta: autoRunMeta(&app, "migrate", image),
+			Spec:       djangov1alpha1.DjangoMigrateSpec{CommandOptions: autoRunOptions(&app, image)},
+		}
+		if err := r.createRun(ctx, &app, runs.migrate, image); err != nil {
+			return ctrl.Result{}, err
+		}
+	case app.Spec.AutoMigrate.CollectStatic && runs.static == nil &&
+		runs.migrate.Status.Phase == djangov1alpha1.PhaseSucceeded:
+		runs.static = &djangov1alpha1.DjangoStatic{
+			ObjectMeta: autoRunMeta(&app, "static", image),
+			Spec:       djangov1alpha1.DjangoStaticSpec{CommandOptions: autoRunOptions(&app, image)},
+		}
+		if err := r.createRun(ctx, &app, runs.static, image); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The Helm reconciler rewrites the status with its own fields only, so it is patched
+	// rather than updated, and patched again whenever it is found missing
+	before := app.DeepCopy()
+	app.Status.AutoMigrate = autoMigrateStatus(&app, image, runs)
+	if equality.Semantic.DeepEqual(before.Status.AutoMigrate, app.Status.AutoMigrate) {
+		return result, nil
+	}
+	return result, r.Status().Patch(ctx, &app, client.MergeFrom(before))
+}
+
+// autoRuns are the automatic runs of a DjangoApp.
+type autoRuns struct {
+	// migrate and static are the runs of the deployed image, if created
+	migrate *djangov1alpha1.DjangoMigrate
+	static  *djangov1alpha1.DjangoStatic
+	// previous is the last succeeded migration of another image
+	previous *djangov1alpha1.DjangoMigrate
+	// history are the migrations of other images, newest first
+	history []djangov1alpha1.DjangoMigrate
+}
+
+// done reports whether every run of the deployed image succeeded.
+func (runs autoRuns) done(am *djangov1alpha1.AutoMigrate) bool {
+	if runs.migrate == nil || runs.migrate.Status.Phase != djangov1alpha1.PhaseSucceeded {
+		return false
+	}
+	return !am.CollectStatic || (runs.static != nil && runs.static.Status.Phase == djangov1alpha1.PhaseSucceeded)
+}
+
+// listAutoRuns returns the automatic runs of app for image.
+func listAutoRuns(ctx context.Context, c client.Reader, app *djangov1alpha1.DjangoApp, image string) (autoRuns, error) {
+	var runs autoRuns
+	var migrations djangov1alpha1.DjangoMigrateList
+	if err := c.List(ctx, &migrations, client.InNamespace(app.Namespace),
+		client.MatchingLabels{autoMigrateLabel: app.Name}); err != nil {
+		return runs, err
+	}
+	sort.Slice(migrations.Items, func(i, j int) bool {
+		return migrations.Items[j].CreationTimestamp.Before(&migrations.Items[i].CreationTimestamp)
+	})
+	for i := range migrations.Items {
+		dm := &migrations.Items[i]
+		if dm.Annotations[imageAnnotation] == image {
+			runs.migrate = dm
+			continue
+		}
+		runs.history = append(runs.history, *dm)
+		if dm.Status.Phase == djangov1alpha1.PhaseSucceeded &&
+			(runs.previous == nil || runs.previous.Status.Applied.Before(dm.Status.Applied)) {
+			runs.previous = dm
+		}
+	}
+
+	if app.Spec.AutoMigrate.CollectStatic {
+		ds := &djangov1alpha1.DjangoStatic{}
+		meta := autoRunMeta(app, "static", image)
+		err := c.Get(ctx, client.ObjectKey{Namespace: meta.Namespace, Name: meta.Name}, ds)
+		switch {
+		case err == nil:
+			runs.static = ds
+		case !errors.IsNotFound(err):
+			return runs, err
+		}
+	}
+	return runs, nil
+}
+
+// trimHistory deletes the finished migrations of previous images, and their collectstatic,
+// beyond autoMigrateHistoryLimit. The last succeeded one is always kept.
+func (r *AutoMigrateReconciler) trimHistory(ctx context.Context, app *djangov1alpha1.DjangoApp, runs autoRuns) error {
+	kept := 0
+	for i := range runs.history {
+		dm := &runs.history[i]
+		if runs.previous != nil && dm.Name == runs.previous.Name {
+			continue
+		}
+		if dm.Status.Phase != djangov1alpha1.PhaseSucceeded && dm.Status.Phase != djangov1alpha1.PhaseFailed {
+			continue
+		}
+		if kept++; kept <= autoMigrateHistoryLimit {
+			continue
+		}
+		static := &djangov1alpha1.DjangoStatic{ObjectMeta: autoRunMeta(app, "static", dm.Annotations[imageAnnotation])}
+		for _, obj := range []client.Object{static, dm} {
+			if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rolledOut reports whether the Django pod commands run in uses image.
+func (r *AutoMigrateReconciler) rolledOut(ctx context.Context, namespace, image string) (bool, error) {
+	pod, err := r.Pods.FindDjangoPod(ctx, namespace)
+	if err != nil || pod == nil {
+		return false, err
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Image == image {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createRun creates the automatic run obj of app for image.
+func (r *AutoMigrateReconciler) createRun(ctx context.Context, app *djangov1alpha1.DjangoApp, obj client.Object, image string) error {
+	if err := controllerutil.SetControllerReference(app, obj, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	gvk, err := r.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	logf.FromContext(ctx).Info("Created automatic run", "kind", gvk.Kind, "name", obj.GetName(), "image", image)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(app, corev1.EventTypeNormal, EventAutoMigrate,
+			"Created %s %s for image %s", gvk.Kind, obj.GetName(), image)
+	}
+	return nil
+}
+
+// autoRunMeta returns the metadata of the automatic run of kind for image. The name is
+// derived from the image, so a run is never created twice for the same one.
+func autoRunMeta(app *djangov1alpha1.DjangoApp, kind, image string) metav1.ObjectMeta {
+	sum := sha256.Sum256([]byte(image))
+	return metav1.ObjectMeta{
+		Name:        fmt.Sprintf("%s-%s-%s", app.Name, kind, hex.EncodeToString(sum[:])[:10]),
+		Namespace:   app.Namespace,
+		Labels:      map[string]string{autoMigrateLabel: app.Name},
+		Annotations: map[string]string{imageAnnotation: image},
+	}
+}
+
+// autoRunOptions returns the command options of the automatic runs of app for image.
+func autoRunOptions(app *djangov1alpha1.DjangoApp, image string) djangov1alpha1.CommandOptions {
+	opts := djangov1alpha1.CommandOptions{RetryPolicy: app.Spec.AutoMigrate.RetryPolicy.DeepCopy()}
+	if app.Spec.AutoMigrate.Order == djangov1alpha1.MigrationOrderPreUpgrade {
+		// The serving pods still run the previous image
+		opts.Image = image
+	}
+	return opts
+}
+
+// autoMigrateStatus summarises runs, the automatic runs of app for image.
+func autoMigrateStatus(app *djangov1alpha1.DjangoApp, image string, runs autoRuns) *djangov1alpha1.AutoMigrateStatus {
+	st := &djangov1alpha1.AutoMigrateStatus{Image: image, Phase: djangov1alpha1.PhasePending}
+	if runs.previous != nil {
+		st.MigratedImage = runs.previous.Annotations[imageAnnotation]
+		st.MigratedAt = runs.previous.Status.Applied
+	}
+	if runs.migrate == nil {
+		return st
+	}
+	st.Migration = runs.migrate.Name
+	st.ImageDigest = runs.migrate.Status.ImageDigest
+	if runs.migrate.Status.Phase != "" {
+		st.Phase = runs.migrate.Status.Phase
+	}
+	if runs.static != nil {
+		st.Static = runs.static.Name
+	}
+	if st.Phase == djangov1alpha1.PhaseSucceeded && app.Spec.AutoMigrate.CollectStatic {
+		st.Phase = djangov1alpha1.PhasePending
+		if runs.static != nil && runs.static.Status.Phase != "" {
+			st.Phase = runs.static.Status.Phase
+		}
+	}
+	if runs.done(app.Spec.AutoMigrate) {
+		st.MigratedImage = image
+		st.MigratedAt = runs.migrate.Status.Applied
+		if runs.static != nil {
+			st.MigratedAt = runs.static.Status.Collected
+		}
+	}
+	return st
+}
+
+// heldImage returns the image a DjangoApp migrating PreUpgrade must stay on because the
+// migrations of the image it asks for have not succeeded yet. It is empty when nothing
+// is held back, including before the first automatic migration succeeded.
+func heldImage(ctx context.Context, c client.Reader, app *djangov1alpha1.DjangoApp, defaults chartutil.Values) (string, error) {
+	if app.Spec.AutoMigrate == nil || app.Spec.AutoMigrate.Order != djangov1alpha1.MigrationOrderPreUpgrade {
+		return "", nil
+	}
+	image, err := appImage(app, defaults)
+	if err != nil || image == "" {
+		return "", err
+	}
+	runs, err := listAutoRuns(ctx, c, app, image)
+	if err != nil || runs.done(app.Spec.AutoMigrate) || runs.previous == nil {
+		return "", err
+	}
+	return runs.previous.Annotations[imageAnnotation], nil
+}
+
+// appImage returns the image the chart deploys for app, as the chart renders it, or
+// nothing if no repository is set.
+func appImage(app *djangov1alpha1.DjangoApp, defaults chartutil.Values) (string, error) {
+	vals, err := AppValues(app, defaults)
+	if err != nil {
+		return "", err
+	}
+	field := func(name string) string {
+		for _, v := range []chartutil.Values{vals, defaults} {
+			if s, err := v.PathValue("image." + name); err == nil && s != nil {
+				return fmt.Sprint(s)
+			}
+		}
+		return ""
+	}
+	repository := field("repository")
+	if repository == "" {
+		return "", nil
+	}
+	return repository + ":" + field("tag"), nil
+}
+
+// withImage sets the image of vals, the values of a DjangoApp, to image.
+func withImage(vals chartutil.Values, image string) {
+	i := strings.LastIndex(image, ":")
+	img, _ := vals["image"].(map[string]interface{})
+	if img == nil {
+		img = map[string]interface{}{}
+		vals["image"] = img
+	}
+	img["repository"], img["tag"] = image[:i], image[i+1:]
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AutoMigrateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var err error
+	if r.Pods, _, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
+		return err
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("automigrate-controller")
+	}
+	if r.Defaults == nil {
+		chartObj, err := charts.Chart()
+		if err != nil {
+			return fmt.Errorf("failed to load embedded chart: %w", err)
+		}
+		r.Defaults = chartObj.Values
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&djangov1alpha1.DjangoApp{}).
+		Owns(&djangov1alpha1.DjangoMigrate{}).
+		Owns(&djangov1alpha1.DjangoStatic{}).
+		Named("automigrate").
+		Complete(r)
+}
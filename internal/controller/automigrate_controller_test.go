@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// imagePodRunner is a fake PodRunner whose Django pod runs image.
+type imagePodRunner struct {
+	testPodRunner
+	image string
+}
+
+func (t *imagePodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.Pod, error) {
+	pod, _ := t.testPodRunner.FindDjangoPod(ctx, ns)
+	pod.Spec.Containers = []corev1.Container{{Name: "django", Image: t.image}}
+	return pod, nil
+}
+
+// chartDefaults mimics the image values of the embedded chart.
+var chartDefaults = chartutil.Values{
+	"image": map[string]interface{}{"repository": "jvela/sample-django", "tag": "latest"},
+}
+
+var _ = Describe("AutoMigrate Controller", func() {
+	Context("When computing the image of a DjangoApp", func() {
+		It("should fall back to the chart defaults", func() {
+			app := &djangov1alpha1.DjangoApp{}
+			Expect(appImage(app, chartDefaults)).To(Equal("jvela/sample-django:latest"))
+
+			app.Spec.Values = &apiextv1.JSON{Raw: []byte(`{"image":{"tag":2.1}}`)}
+			Expect(appImage(app, chartDefaults)).To(Equal("jvela/sample-django:2.1"))
+
+			app.Spec.Values = &apiextv1.JSON{Raw: []byte(`{"image":{"repository":"registry:5000/shop","tag":"v1"}}`)}
+			Expect(appImage(app, chartDefaults)).To(Equal("registry:5000/shop:v1"))
+			Expect(appImage(app, nil)).To(Equal("registry:5000/shop:v1"))
+		})
+
+		It("should set the repository and tag of an image", func() {
+			vals := chartutil.Values{"image": map[string]interface{}{"pullPolicy": "Always"}}
+			withImage(vals, "registry:5000/shop:v1")
+			Expect(vals["image"]).To(Equal(map[string]interface{}{
+				"repository": "registry:5000/shop", "tag": "v1", "pullPolicy": "Always",
+			}))
+		})
+	})
+
+	Context("When migrating before upgrades", func() {
+		ctx := context.Background()
+		appName := types.NamespacedName{Name: "auto-pre", Namespace: "default"}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+				Spec: djangov1alpha1.DjangoAppSpec{
+					Values: &apiextv1.JSON{Raw: []byte(`{"image":{"repository":"registry/shop","tag":"v1"}}`)},
+					AutoMigrate: &djangov1alpha1.AutoMigrate{
+						Order:         djangov1alpha1.MigrationOrderPreUpgrade,
+						CollectStatic: true,
+					},
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.DeleteAllOf(ctx, &djangov1alpha1.DjangoMigrate{}, client.InNamespace("default"),
+				client.MatchingLabels{autoMigrateLabel: appName.Name})).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &djangov1alpha1.DjangoStatic{}, client.InNamespace("default"),
+				client.MatchingLabels{autoMigrateLabel: appName.Name})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should migrate every new image and hold the release until it succeeds", func() {
+			controllerReconciler := &AutoMigrateReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Pods:     testPodRunner{},
+				Defaults: chartDefaults,
+			}
+			reconcileApp := func() {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			app := &djangov1alpha1.DjangoApp{}
+			translate := func() chartutil.Values {
+				Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+				obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(app)
+				Expect(err).NotTo(HaveOccurred())
+				vals, err := specTranslator(k8sClient, chartDefaults).Translate(ctx, &unstructured.Unstructured{Object: obj})
+				Expect(err).NotTo(HaveOccurred())
+				return vals
+			}
+
+			By("migrating the first image in a Job running it")
+			reconcileApp()
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			dm := &djangov1alpha1.DjangoMigrate{}
+			dmName := autoRunMeta(app, "migrate", "registry/shop:v1").Name
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dmName, Namespace: "default"}, dm)).To(Succeed())
+			Expect(dm.Spec.Image).To(Equal("registry/shop:v1"))
+			Expect(dm.OwnerReferences).To(HaveLen(1))
+			Expect(app.Status.AutoMigrate).NotTo(BeNil())
+			Expect(app.Status.AutoMigrate.Migration).To(Equal(dmName))
+			Expect(app.Status.AutoMigrate.Phase).To(Equal(djangov1alpha1.PhasePending))
+			Expect(translate()).To(HaveKeyWithValue("image", HaveKeyWithValue("tag", "v1")))
+
+			By("collecting static files once migrated")
+			dm.Status.Phase = djangov1alpha1.PhaseSucceeded
+			now := metav1.Now()
+			dm.Status.Applied = &now
+			dm.Status.ImageDigest = "registry/shop@sha256:0123"
+			Expect(k8sClient.Status().Update(ctx, dm)).To(Succeed())
+			reconcileApp()
+			ds := &djangov1alpha1.DjangoStatic{}
+			dsName := autoRunMeta(app, "static", "registry/shop:v1").Name
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: dsName, Namespace: "default"}, ds)).To(Succeed())
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(app.Status.AutoMigrate.Phase).To(Equal(djangov1alpha1.PhasePending))
+
+			ds.Status.Phase = djangov1alpha1.PhaseSucceeded
+			ds.Status.Collected = dm.Status.Applied
+			Expect(k8sClient.Status().Update(ctx, ds)).To(Succeed())
+			reconcileApp()
+			Expect(k8sClient.Get(ctx, appName, app)).To(Succeed())
+			Expect(app.Status.AutoMigrate.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(app.Status.AutoMigrate.MigratedImage).To(Equal("registry/shop:v1"))
+			Expect(app.Status.AutoMigrate.ImageDigest).To(Equal("registry/shop@sha256:0123"))
+
+			By("holding a new image on the migrated one")
+			app.Spec.Values = &apiextv1.JSON{Raw: []byte(`{"image":{"repository":"registry/shop","tag":"v2"}}`)}
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+			reconcileApp()
+			Expect(k8sClient.Get(ctx, types.NamespacedName{
+				Name: autoRunMeta(app, "migrate", "registry/shop:v2").Name, Namespace: "default",
+			}, dm)).To(Succeed())
+			Expect(translate()).To(HaveKeyWithValue("image", HaveKeyWithValue("tag", "v1")))
+			Expect(app.Status.AutoMigrate.Image).To(Equal("registry/shop:v2"))
+			Expect(app.Status.AutoMigrate.MigratedImage).To(Equal("registry/shop:v1"))
+		})
+	})
+
+	Context("When migrating after upgrades", func() {
+		ctx := context.Background()
+		appName := types.NamespacedName{Name: "auto-post", Namespace: "default"}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+				Spec: djangov1alpha1.DjangoAppSpec{
+					Values:      &apiextv1.JSON{Raw: []byte(`{"image":{"repository":"registry/shop","tag":"v3"}}`)},
+					AutoMigrate: &djangov1alpha1.AutoMigrate{},
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.DeleteAllOf(ctx, &djangov1alpha1.DjangoMigrate{}, client.InNamespace("default"),
+				client.MatchingLabels{autoMigrateLabel: appName.Name})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoApp{
+				ObjectMeta: metav1.ObjectMeta{Name: appName.Name, Namespace: appName.Namespace},
+			})).To(Succeed())
+		})
+
+		It("should wait for the new image to roll out", func() {
+			pods := &imagePodRunner{image: "registry/shop:v2"}
+			controllerReconciler := &AutoMigrateReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Pods:     pods,
+				Defaults: chartDefaults,
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			var migrations djangov1alpha1.DjangoMigrateList
+			Expect(k8sClient.List(ctx, &migrations, client.MatchingLabels{autoMigrateLabel: appName.Name})).To(Succeed())
+			Expect(migrations.Items).To(BeEmpty())
+
+			pods.image = "registry/shop:v3"
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: appName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.List(ctx, &migrations, client.MatchingLabels{autoMigrateLabel: appName.Name})).To(Succeed())
+			Expect(migrations.Items).To(HaveLen(1))
+			Expect(migrations.Items[0].Spec.Image).To(BeEmpty())
+		})
+	})
+})
@@ -18,25 +18,26 @@ package controller
 
 import (
 	"context"
-	"time"
 
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // DjangoCeleryReconciler reconciles a DjangoCelery object
 type DjangoCeleryReconciler struct {
 	client.Client
-	Scheme         *runtime.Scheme
-	Pods           PodRunner
+	Scheme *runtime.Scheme
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container      string
 	DjangoPodlabel PodLabel
-	KeepCRs        int
+	Recorder       record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=django.djangooperator,resources=djangoceleries,verbs=get;list;watch;create;update;patch;delete
@@ -45,29 +46,35 @@ type DjangoCeleryReconciler struct {
 
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
 func (r *DjangoCeleryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := logf.FromContext(ctx)
-	// Fetch the DjangoUser
-	var dc djangov1alpha1.DjangoCelery
-	if err := r.Get(ctx, req.NamespacedName, &dc); err != nil {
-		if errors.IsNotFound(err) {
-			// CR deleted, nothing to do
-			return ctrl.Result{}, nil
-		}
-		return ctrl.Result{}, err
-	}
-	// Skip if already created
-	if !dc.Status.Executed.IsZero() {
-		return ctrl.Result{}, nil
-	}
-	pod, err := r.Pods.FindDjangoPod(ctx, req.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	if pod == nil {
-		logger.Info("no django-server pod found; retrying shortly")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	return r.commands().Reconcile(ctx, req)
+}
+
+// commands returns the shared one-shot reconciler running celery for DjangoCelerys.
+func (r *DjangoCeleryReconciler) commands() commandReconciler[*djangov1alpha1.DjangoCelery] {
+	return commandReconciler[*djangov1alpha1.DjangoCelery]{
+		Client:    r.Client,
+		Pods:      r.Pods,
+		Jobs:      r.Jobs,
+		Runner:    r.Runner,
+		Container: r.Container,
+		Recorder:  r.Recorder,
+		Task: commandTask[*djangov1alpha1.DjangoCelery]{
+			Kind: "DjangoCelery",
+			New:  func() *djangov1alpha1.DjangoCelery { return &djangov1alpha1.DjangoCelery{} },
+			Done: func(dc *djangov1alpha1.DjangoCelery) bool { return !dc.Status.Executed.IsZero() },
+			Command: func(_ context.Context, dc *djangov1alpha1.DjangoCelery) (ExecRequest, error) {
+				return ExecRequest{Command: celeryCommand(dc)}, nil
+			},
+			Succeeded: func(dc *djangov1alpha1.DjangoCelery, _ ExecResult, now metav1.Time) string {
+				dc.Status.Executed = &now
+				return ""
+			},
+		},
 	}
-	// Build the command
+}
+
+// celeryCommand builds the celery purge or revoke command of dc.
+func celeryCommand(dc *djangov1alpha1.DjangoCelery) []string {
 	shellCmd := []string{
 		"celery", "-A", dc.Spec.App,
 	}
@@ -78,44 +85,18 @@ func (r *DjangoCeleryReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	} else {
 		shellCmd = append(shellCmd, "purge", "-f")
 	}
-	// Exec command
-	if err := r.Pods.ExecInPod(ctx, pod, shellCmd); err != nil {
-		logger.Error(err, "failed to exec celery command", shellCmd, "pod", pod.Name)
-		return ctrl.Result{}, err
-	}
-
-	// Update status.Applied
-	dc.Status.Executed = metav1.Now()
-	if err := r.Status().Update(ctx, &dc); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	logger.Info("Celery", "exec", dc.Name)
-
-	// keep only the most-recent DjangoCelery objects
-	celeryGVK := djangov1alpha1.GroupVersion.WithKind("DjangoCelery")
-	if err := pruneOldCRs(r.Client, ctx, celeryGVK, req.Namespace, r.KeepCRs); err != nil {
-		return ctrl.Result{}, err
-	}
-	return ctrl.Result{}, nil
-
+	return shellCmd
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DjangoCeleryReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// initialize REST config & clientset
-	RestCFG := mgr.GetConfig()
-	cs, err := kubernetes.NewForConfig(RestCFG)
-	if err != nil {
+	// wire in the real PodRunners
+	var err error
+	if r.Pods, r.Jobs, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
 		return err
 	}
-
-	// wire in the real PodRunner
-	r.Pods = DjangoPodRunner{
-		Client:    r.Client,
-		RESTCfg:   RestCFG,
-		Clientset: cs,
-		Label:     r.DjangoPodlabel,
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangocelery-controller")
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
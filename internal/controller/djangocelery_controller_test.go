@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -87,6 +88,9 @@ var _ = Describe("DjangoCelery Controller", func() {
 
 			// Ensure Status.Created is non-zero
 			Expect(updated.Status.Executed.IsZero()).To(BeFalse(), "expected Status.Executed to be set")
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(1))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, djangov1alpha1.ConditionReady)).To(BeTrue())
 
 		})
 	})
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// allowAllCommands in the allowlist allows every management command.
+const allowAllCommands = "*"
+
+// DjangoCommandReconciler reconciles a DjangoCommand object
+type DjangoCommandReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container      string
+	DjangoPodlabel PodLabel
+	Recorder       record.EventRecorder
+	// Allowed lists the management commands that can be run, "*" for all of them; empty
+	// allows none
+	Allowed []string
+}
+
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocommands,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocommands/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocommands/finalizers,verbs=update
+
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
+func (r *DjangoCommandReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.commands().Reconcile(ctx, req)
+}
+
+// commands returns the shared one-shot reconciler running management commands for DjangoCommands.
+func (r *DjangoCommandReconciler) commands() commandReconciler[*djangov1alpha1.DjangoCommand] {
+	return commandReconciler[*djangov1alpha1.DjangoCommand]{
+		Client:    r.Client,
+		Pods:      r.Pods,
+		Jobs:      r.Jobs,
+		Runner:    r.Runner,
+		Container: r.Container,
+		Recorder:  r.Recorder,
+		Task: commandTask[*djangov1alpha1.DjangoCommand]{
+			Kind: "DjangoCommand",
+			New:  func() *djangov1alpha1.DjangoCommand { return &djangov1alpha1.DjangoCommand{} },
+			Done: func(dc *djangov1alpha1.DjangoCommand) bool { return !dc.Status.Executed.IsZero() },
+			Command: func(_ context.Context, dc *djangov1alpha1.DjangoCommand) (ExecRequest, error) {
+				return managementCommand(dc, r.Allowed)
+			},
+			Succeeded: func(dc *djangov1alpha1.DjangoCommand, _ ExecResult, now metav1.Time) string {
+				dc.Status.Executed = &now
+				return ""
+			},
+		},
+	}
+}
+
+// managementCommand builds the request running the management command of dc, if the
+// allowlist permits it.
+func managementCommand(dc *djangov1alpha1.DjangoCommand, allowed []string) (ExecRequest, error) {
+	if !commandAllowed(allowed, dc.Spec.Command) {
+		// Retrying cannot help: the CR stays Failed until its spec changes, even once the
+		// allowlist permits the command
+		return ExecRequest{}, &permanentError{
+			reason: ReasonCommandNotAllowed,
+			err:    fmt.Errorf("command %q is not in the operator's allowlist", dc.Spec.Command),
+		}
+	}
+	env := make([]corev1.EnvVar, 0, len(dc.Spec.Env))
+	for _, e := range dc.Spec.Env {
+		env = append(env, corev1.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	return ExecRequest{
+		Command: append([]string{"python", "manage.py", dc.Spec.Command}, dc.Spec.Args...),
+		Env:     env,
+	}, nil
+}
+
+// commandAllowed reports whether command may be run under the allowlist. Commands such
+// as flush or dbshell are destructive, so nothing is allowed unless listed.
+func commandAllowed(allowed []string, command string) bool {
+	return slices.Contains(allowed, allowAllCommands) || slices.Contains(allowed, command)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DjangoCommandReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// wire in the real PodRunners
+	var err error
+	if r.Pods, r.Jobs, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
+		return err
+	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangocommand-controller")
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&djangov1alpha1.DjangoCommand{}).
+		Named("djangocommand").
+		Complete(r)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("DjangoCommand Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		djangocommand := &djangov1alpha1.DjangoCommand{}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind DjangoCommand")
+			err := k8sClient.Get(ctx, typeNamespacedName, djangocommand)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &djangov1alpha1.DjangoCommand{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: djangov1alpha1.DjangoCommandSpec{
+						Command: "loaddata",
+						Args:    []string{"fixtures.json", "--verbosity", "2"},
+						Env:     []djangov1alpha1.CommandEnvVar{{Name: "DJANGO_SETTINGS_MODULE", Value: "app.settings.fixtures"}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &djangov1alpha1.DjangoCommand{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance DjangoCommand")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should run the management command with its args and env", func() {
+			runner := &recordingPodRunner{}
+			controllerReconciler := &DjangoCommandReconciler{
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Pods:    runner,
+				Allowed: []string{"loaddata"},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(runner.requests).To(HaveLen(1))
+			Expect(runner.requests[0].Command).To(Equal(
+				[]string{"python", "manage.py", "loaddata", "fixtures.json", "--verbosity", "2"}))
+			Expect(runner.requests[0].Env).To(Equal(
+				[]corev1.EnvVar{{Name: "DJANGO_SETTINGS_MODULE", Value: "app.settings.fixtures"}}))
+
+			updated := &djangov1alpha1.DjangoCommand{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Executed).NotTo(BeNil())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+		})
+
+		It("should refuse commands outside the allowlist without retrying", func() {
+			runner := &recordingPodRunner{}
+			controllerReconciler := &DjangoCommandReconciler{
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Pods:    runner,
+				Allowed: []string{"clearsessions", "createcachetable"},
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(runner.requests).To(BeEmpty())
+
+			updated := &djangov1alpha1.DjangoCommand{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseFailed))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, djangov1alpha1.ConditionFailed)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonCommandNotAllowed))
+		})
+	})
+
+	Context("When checking the allowlist", func() {
+		It("should allow only listed commands, and none by default", func() {
+			Expect(commandAllowed(nil, "flush")).To(BeFalse())
+			Expect(commandAllowed([]string{"clearsessions"}, "clearsessions")).To(BeTrue())
+			Expect(commandAllowed([]string{"clearsessions"}, "dbshell")).To(BeFalse())
+			Expect(commandAllowed([]string{allowAllCommands}, "dbshell")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,308 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// cronJobLabel is set on every run to the name of the DjangoCronJob that created it.
+	cronJobLabel = "django.djangooperator/cronjob"
+	// scheduledTimeAnnotation records the scheduled time a run was created for.
+	scheduledTimeAnnotation = "django.djangooperator/scheduled-at"
+	// maxMissedRuns bounds the scheduled times walked since the last run, as batch CronJob does.
+	maxMissedRuns = 100
+)
+
+var (
+	errScheduleNeverFires = errors.New("schedule never fires")
+	errTooManyMissedRuns  = fmt.Errorf("too many missed start times (> %d), set or decrease "+
+		".spec.startingDeadlineSeconds or check clock skew", maxMissedRuns)
+)
+
+// cronRunKinds are the kinds a DjangoCronJob can create runs of.
+var cronRunKinds = []string{"DjangoMigrate", "DjangoStatic", "DjangoCelery", "DjangoCommand"}
+
+// DjangoCronJobReconciler reconciles a DjangoCronJob object
+type DjangoCronJobReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// now is the clock used to evaluate schedules; nil means time.Now
+	now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocronjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangocronjobs/finalizers,verbs=update
+
+// Reconcile mirrors the batch CronJob controller: it refreshes the list of in-flight
+// runs, trims the run history, and creates the most recent missed run, if any, honouring
+// suspend, the starting deadline and the concurrency policy.
+func (r *DjangoCronJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	var cj djangov1alpha1.DjangoCronJob
+	if err := r.Get(ctx, req.NamespacedName, &cj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// CR deleted, its runs are garbage collected through their owner reference
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	runs, err := r.listRuns(ctx, &cj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var active, succeeded, failed []unstructured.Unstructured
+	for _, u := range runs {
+		switch djangov1alpha1.CommandPhase(stringField(u, "status", "phase")) {
+		case djangov1alpha1.PhaseSucceeded:
+			succeeded = append(succeeded, u)
+		case djangov1alpha1.PhaseFailed:
+			failed = append(failed, u)
+		default:
+			active = append(active, u)
+		}
+	}
+	cj.Status.Active = runRefs(active)
+	for _, u := range succeeded {
+		if t := completionTime(u); cj.Status.LastSuccessfulTime == nil || t.After(cj.Status.LastSuccessfulTime.Time) {
+			cj.Status.LastSuccessfulTime = ptr.To(metav1.NewTime(t))
+		}
+	}
+	if err := r.trimHistory(ctx, succeeded, ptr.Deref(cj.Spec.SuccessfulRunsHistoryLimit, 3)); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.trimHistory(ctx, failed, ptr.Deref(cj.Spec.FailedRunsHistoryLimit, 1)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	schedule, err := cron.ParseStandard(cj.Spec.Schedule)
+	if err != nil {
+		logger.Error(err, "invalid schedule", "schedule", cj.Spec.Schedule)
+		setCronCondition(&cj, metav1.ConditionFalse, ReasonInvalidSchedule, err.Error())
+		// Nothing to retry until the spec changes
+		return ctrl.Result{}, r.Status().Update(ctx, &cj)
+	}
+	if ptr.Deref(cj.Spec.Suspend, false) {
+		setCronCondition(&cj, metav1.ConditionTrue, ReasonScheduled, "suspended")
+		return ctrl.Result{}, r.Status().Update(ctx, &cj)
+	}
+
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+	missed, next, err := missedRun(&cj, schedule, now)
+	if err != nil {
+		reason := ReasonInvalidSchedule
+		if errors.Is(err, errTooManyMissedRuns) {
+			reason = ReasonTooManyMissedRuns
+		}
+		logger.Error(err, "cannot schedule", "schedule", cj.Spec.Schedule)
+		setCronCondition(&cj, metav1.ConditionFalse, reason, err.Error())
+		// Nothing to retry until the spec changes
+		return ctrl.Result{}, r.Status().Update(ctx, &cj)
+	}
+	result := ctrl.Result{RequeueAfter: next.Sub(now)}
+	setCronCondition(&cj, metav1.ConditionTrue, ReasonScheduled, "next run at "+next.UTC().Format(time.RFC3339))
+	if missed.IsZero() {
+		return result, r.Status().Update(ctx, &cj)
+	}
+
+	if cj.Spec.ConcurrencyPolicy == djangov1alpha1.ConcurrencyForbid && len(active) > 0 {
+		logger.Info("previous run still in flight, skipping", "scheduled", missed)
+		return result, r.Status().Update(ctx, &cj)
+	}
+	if cj.Spec.ConcurrencyPolicy == djangov1alpha1.ConcurrencyReplace {
+		for _, u := range active {
+			if err := r.Delete(ctx, &u); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Info("replaced in-flight run", "kind", u.GetKind(), "name", u.GetName())
+		}
+		cj.Status.Active = nil
+	}
+
+	run, err := runFor(&cj, missed)
+	if err != nil {
+		setCronCondition(&cj, metav1.ConditionFalse, ReasonInvalidTemplate, err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, &cj)
+	}
+	if err := controllerutil.SetControllerReference(&cj, run, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, run); err != nil && !apierrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Created scheduled run", "name", run.GetName(), "scheduled", missed)
+
+	gvk, err := r.GroupVersionKindFor(run)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	cj.Status.Active = append(cj.Status.Active, corev1.ObjectReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  run.GetNamespace(),
+		Name:       run.GetName(),
+		UID:        run.GetUID(),
+	})
+	cj.Status.LastScheduleTime = &metav1.Time{Time: missed}
+	return result, r.Status().Update(ctx, &cj)
+}
+
+// listRuns returns the runs created by cj, of every kind, newest-first.
+func (r *DjangoCronJobReconciler) listRuns(ctx context.Context, cj *djangov1alpha1.DjangoCronJob) ([]unstructured.Unstructured, error) {
+	var runs []unstructured.Unstructured
+	for _, kind := range cronRunKinds {
+		ul := &unstructured.UnstructuredList{}
+		ul.SetGroupVersionKind(djangov1alpha1.GroupVersion.WithKind(kind))
+		if err := r.List(ctx, ul, client.InNamespace(cj.Namespace), client.MatchingLabels{cronJobLabel: cj.Name}); err != nil {
+			return nil, err
+		}
+		for _, u := range ul.Items {
+			if metav1.IsControlledBy(&u, cj) {
+				runs = append(runs, u)
+			}
+		}
+	}
+	sortNewestFirst(runs)
+	return runs, nil
+}
+
+// trimHistory deletes the runs, sorted newest-first, beyond the first limit.
+func (r *DjangoCronJobReconciler) trimHistory(ctx context.Context, runs []unstructured.Unstructured, limit int32) error {
+	for i := int(limit); i < len(runs); i++ {
+		if err := r.Delete(ctx, &runs[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missedRun returns the most recent scheduled time that has passed without a run (zero
+// if there is none, or if it is older than the starting deadline) and the next one. It
+// fails for schedules that never fire, e.g. on February 30th, and when more than
+// maxMissedRuns times have passed.
+func missedRun(cj *djangov1alpha1.DjangoCronJob, schedule cron.Schedule, now time.Time) (time.Time, time.Time, error) {
+	earliest := cj.CreationTimestamp.Time
+	if cj.Status.LastScheduleTime != nil {
+		earliest = cj.Status.LastScheduleTime.Time
+	}
+	if cj.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*cj.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	var missed time.Time
+	next := schedule.Next(earliest)
+	for n := 0; ; n++ {
+		// cron returns the zero time when no time matches within five years
+		if next.IsZero() {
+			return time.Time{}, time.Time{}, errScheduleNeverFires
+		}
+		if next.After(now) {
+			return missed, next, nil
+		}
+		if n == maxMissedRuns {
+			return time.Time{}, time.Time{}, errTooManyMissedRuns
+		}
+		missed = next
+		next = schedule.Next(next)
+	}
+}
+
+// runFor builds the run of cj scheduled at t from its template.
+func runFor(cj *djangov1alpha1.DjangoCronJob, t time.Time) (client.Object, error) {
+	meta := metav1.ObjectMeta{
+		// Deterministic, so a run is never created twice for the same time
+		Name:        fmt.Sprintf("%s-%d", cj.Name, t.Unix()/60),
+		Namespace:   cj.Namespace,
+		Labels:      map[string]string{cronJobLabel: cj.Name},
+		Annotations: map[string]string{scheduledTimeAnnotation: t.UTC().Format(time.RFC3339)},
+	}
+	tmpl := cj.Spec.Template
+	switch {
+	case tmpl.Migrate != nil:
+		return &djangov1alpha1.DjangoMigrate{ObjectMeta: meta, Spec: *tmpl.Migrate.DeepCopy()}, nil
+	case tmpl.Static != nil:
+		return &djangov1alpha1.DjangoStatic{ObjectMeta: meta, Spec: *tmpl.Static.DeepCopy()}, nil
+	case tmpl.Celery != nil:
+		return &djangov1alpha1.DjangoCelery{ObjectMeta: meta, Spec: *tmpl.Celery.DeepCopy()}, nil
+	case tmpl.Command != nil:
+		return &djangov1alpha1.DjangoCommand{ObjectMeta: meta, Spec: *tmpl.Command.DeepCopy()}, nil
+	default:
+		return nil, fmt.Errorf("template of DjangoCronJob %s sets no run", cj.Name)
+	}
+}
+
+func runRefs(runs []unstructured.Unstructured) []corev1.ObjectReference {
+	var refs []corev1.ObjectReference
+	for _, u := range runs {
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+			UID:        u.GetUID(),
+		})
+	}
+	return refs
+}
+
+func setCronCondition(cj *djangov1alpha1.DjangoCronJob, status metav1.ConditionStatus, reason, msg string) {
+	meta.SetStatusCondition(&cj.Status.Conditions, metav1.Condition{
+		Type:               djangov1alpha1.ConditionReady,
+		Status:             status,
+		ObservedGeneration: cj.Generation,
+		Reason:             reason,
+		Message:            msg,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DjangoCronJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&djangov1alpha1.DjangoCronJob{}).
+		Owns(&djangov1alpha1.DjangoMigrate{}).
+		Owns(&djangov1alpha1.DjangoStatic{}).
+		Owns(&djangov1alpha1.DjangoCelery{}).
+		Owns(&djangov1alpha1.DjangoCommand{}).
+		Named("djangocronjob").
+		Complete(r)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("DjangoCronJob Controller", func() {
+	Context("When computing the schedule", func() {
+		schedule, _ := cron.ParseStandard("0 * * * *")
+		created := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+		cj := func() *djangov1alpha1.DjangoCronJob {
+			return &djangov1alpha1.DjangoCronJob{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			}
+		}
+
+		It("should not run before the first scheduled time", func() {
+			missed, next, err := missedRun(cj(), schedule, created.Add(20*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed.IsZero()).To(BeTrue())
+			Expect(next).To(Equal(time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)))
+		})
+
+		It("should only return the most recent missed run", func() {
+			missed, next, err := missedRun(cj(), schedule, created.Add(3*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed).To(Equal(time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)))
+			Expect(next).To(Equal(time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)))
+		})
+
+		It("should not return runs already scheduled or past the starting deadline", func() {
+			c := cj()
+			c.Status.LastScheduleTime = ptr.To(metav1.NewTime(time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)))
+			missed, _, err := missedRun(c, schedule, created.Add(3*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed.IsZero()).To(BeTrue())
+
+			c = cj()
+			c.Spec.StartingDeadlineSeconds = ptr.To(int64(60))
+			missed, _, err = missedRun(c, schedule, time.Date(2025, 1, 1, 13, 5, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed.IsZero()).To(BeTrue())
+		})
+
+		It("should fail for a schedule that never fires", func() {
+			never, err := cron.ParseStandard("0 0 30 2 *")
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = missedRun(cj(), never, created.Add(3*time.Hour))
+			Expect(err).To(MatchError(errScheduleNeverFires))
+		})
+
+		It("should give up after too many missed runs", func() {
+			every, _ := cron.ParseStandard("* * * * *")
+			_, _, err := missedRun(cj(), every, created.Add(3*time.Hour))
+			Expect(err).To(MatchError(errTooManyMissedRuns))
+
+			By("walking only the times within the starting deadline")
+			c := cj()
+			c.Spec.StartingDeadlineSeconds = ptr.To(int64(300))
+			missed, _, err := missedRun(c, every, created.Add(3*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(missed).To(Equal(created.Add(3 * time.Hour)))
+		})
+	})
+
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-cronjob"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		// reconcileAt reconciles the cronjob as if it were `after` past its creation.
+		reconcileAt := func(after time.Duration) {
+			cj := &djangov1alpha1.DjangoCronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cj)).To(Succeed())
+			r := &DjangoCronJobReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				now:    func() time.Time { return cj.CreationTimestamp.Add(after) },
+			}
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		runs := func() []djangov1alpha1.DjangoStatic {
+			var list djangov1alpha1.DjangoStaticList
+			Expect(k8sClient.List(ctx, &list, client.InNamespace("default"),
+				client.MatchingLabels{cronJobLabel: resourceName})).To(Succeed())
+			return list.Items
+		}
+		create := func(spec djangov1alpha1.DjangoCronJobSpec) {
+			spec.Template = djangov1alpha1.DjangoCronJobTemplate{Static: &djangov1alpha1.DjangoStaticSpec{}}
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoCronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec:       spec,
+			})).To(Succeed())
+		}
+
+		AfterEach(func() {
+			cj := &djangov1alpha1.DjangoCronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cj)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, cj)).To(Succeed())
+			// envtest runs no garbage collector, so delete the runs by hand
+			Expect(k8sClient.DeleteAllOf(ctx, &djangov1alpha1.DjangoStatic{}, client.InNamespace("default"),
+				client.MatchingLabels{cronJobLabel: resourceName})).To(Succeed())
+		})
+
+		It("should create an owned run when one is due", func() {
+			create(djangov1alpha1.DjangoCronJobSpec{Schedule: "* * * * *"})
+
+			reconcileAt(30 * time.Second)
+			Expect(runs()).To(BeEmpty())
+
+			reconcileAt(90 * time.Second)
+			Expect(runs()).To(HaveLen(1))
+			run := runs()[0]
+			Expect(run.OwnerReferences).To(HaveLen(1))
+			Expect(run.OwnerReferences[0].Name).To(Equal(resourceName))
+			Expect(run.Annotations).To(HaveKey(scheduledTimeAnnotation))
+
+			cj := &djangov1alpha1.DjangoCronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cj)).To(Succeed())
+			Expect(cj.Status.LastScheduleTime).NotTo(BeNil())
+			Expect(cj.Status.Active).To(HaveLen(1))
+			Expect(meta.IsStatusConditionTrue(cj.Status.Conditions, djangov1alpha1.ConditionReady)).To(BeTrue())
+		})
+
+		It("should not start a run while the previous one is in flight with Forbid", func() {
+			create(djangov1alpha1.DjangoCronJobSpec{
+				Schedule:          "* * * * *",
+				ConcurrencyPolicy: djangov1alpha1.ConcurrencyForbid,
+			})
+			reconcileAt(90 * time.Second)
+			reconcileAt(150 * time.Second)
+			Expect(runs()).To(HaveLen(1))
+		})
+
+		It("should not create runs while suspended", func() {
+			create(djangov1alpha1.DjangoCronJobSpec{Schedule: "* * * * *", Suspend: ptr.To(true)})
+			reconcileAt(5 * time.Minute)
+			Expect(runs()).To(BeEmpty())
+		})
+
+		It("should report an invalid schedule", func() {
+			create(djangov1alpha1.DjangoCronJobSpec{Schedule: "not a schedule"})
+			reconcileAt(time.Minute)
+
+			cj := &djangov1alpha1.DjangoCronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cj)).To(Succeed())
+			cond := meta.FindStatusCondition(cj.Status.Conditions, djangov1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonInvalidSchedule))
+		})
+
+		It("should report a schedule that never fires", func() {
+			create(djangov1alpha1.DjangoCronJobSpec{Schedule: "0 0 30 2 *"})
+			reconcileAt(time.Minute)
+
+			cj := &djangov1alpha1.DjangoCronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cj)).To(Succeed())
+			cond := meta.FindStatusCondition(cj.Status.Conditions, djangov1alpha1.ConditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(ReasonInvalidSchedule))
+			Expect(cond.Message).To(Equal(errScheduleNeverFires.Error()))
+		})
+	})
+})
@@ -18,25 +18,31 @@ package controller
 
 import (
 	"context"
-	"time"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // DjangoMigrateReconciler reconciles a DjangoMigrate object
 type DjangoMigrateReconciler struct {
 	client.Client
-	Scheme         *runtime.Scheme
-	Pods           PodRunner
+	Scheme *runtime.Scheme
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container      string
 	DjangoPodlabel PodLabel
-	KeepCRs        int
+	Recorder       record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=django.djangooperator,resources=djangomigrates,verbs=get;list;watch;create;update;patch;delete
@@ -45,29 +51,130 @@ type DjangoMigrateReconciler struct {
 
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
 func (r *DjangoMigrateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := logf.FromContext(ctx)
-	// Fetch the DjangoUser
-	var dm djangov1alpha1.DjangoMigrate
-	if err := r.Get(ctx, req.NamespacedName, &dm); err != nil {
-		if errors.IsNotFound(err) {
-			// CR deleted, nothing to do
-			return ctrl.Result{}, nil
+	return r.commands().Reconcile(ctx, req)
+}
+
+// commands returns the shared one-shot reconciler running migrate for DjangoMigrates.
+func (r *DjangoMigrateReconciler) commands() commandReconciler[*djangov1alpha1.DjangoMigrate] {
+	return commandReconciler[*djangov1alpha1.DjangoMigrate]{
+		Client:    r.Client,
+		Pods:      r.Pods,
+		Jobs:      r.Jobs,
+		Runner:    r.Runner,
+		Container: r.Container,
+		Recorder:  r.Recorder,
+		Task: commandTask[*djangov1alpha1.DjangoMigrate]{
+			Kind: "DjangoMigrate",
+			New:  func() *djangov1alpha1.DjangoMigrate { return &djangov1alpha1.DjangoMigrate{} },
+			Done: func(dm *djangov1alpha1.DjangoMigrate) bool {
+				if dm.Status.Applied.IsZero() {
+					// Nothing to do until the plan is approved
+					return planning(dm) && dm.Status.Plan != nil && dm.Status.Plan.Generation == dm.Generation
+				}
+				// Every CR is reconciled on start-up, which restores the metric after a restart
+				lastMigration.observe(dm.Namespace, dm.Status.Applied.Time)
+				return true
+			},
+			Command: func(ctx context.Context, dm *djangov1alpha1.DjangoMigrate) (ExecRequest, error) {
+				var targets []djangov1alpha1.AppMigrationState
+				if dm.Spec.RollbackTo != "" {
+					target, err := r.rollbackTarget(ctx, dm)
+					if err != nil {
+						return ExecRequest{}, err
+					}
+					dm.Status.RollbackOf = target.Name
+					targets = target.Status.PreApply.Apps
+				}
+				switch {
+				case planning(dm) && dm.Spec.RollbackTo != "":
+					return rollbackCommand(dm, targets, true)
+				case planning(dm):
+					return ExecRequest{Command: planCommand(dm)}, nil
+				case !preApplyRecorded(dm):
+					return ExecRequest{Command: showMigrationsCommand(dm, targets)}, nil
+				case dm.Spec.RollbackTo != "":
+					return rollbackCommand(dm, targets, false)
+				}
+				return ExecRequest{Command: migrateCommand(dm)}, nil
+			},
+			Succeeded: func(dm *djangov1alpha1.DjangoMigrate, res ExecResult, now metav1.Time) string {
+				if planning(dm) {
+					recordPlan(dm, res.Stdout, now)
+					return EventPlanRecorded
+				}
+				if !preApplyRecorded(dm) {
+					recordPreApply(dm, res.Stdout, now)
+					return EventPreApplyRecorded
+				}
+				dm.Status.Applied = &now
+				lastMigration.observe(dm.Namespace, now.Time)
+				return ""
+			},
+			// Migrations and collectstatic must not race each other
+			Exclusive: true,
+		},
+	}
+}
+
+// rollbackTarget returns the applied DjangoMigrate the spec.rollbackTo of dm refers to.
+func (r *DjangoMigrateReconciler) rollbackTarget(ctx context.Context, dm *djangov1alpha1.DjangoMigrate) (*djangov1alpha1.DjangoMigrate, error) {
+	name := dm.Spec.RollbackTo
+	if name == djangov1alpha1.MigrationRollbackPrevious {
+		// Once resolved for the current spec, later steps and retries keep rolling back
+		// the same DjangoMigrate even if a newer one was applied meanwhile
+		name = ""
+		if preApplyRecorded(dm) || (dm.Status.Plan != nil && dm.Status.Plan.Generation == dm.Generation) {
+			name = dm.Status.RollbackOf
 		}
-		return ctrl.Result{}, err
 	}
-	// Skip if already created
-	if !dm.Status.Applied.IsZero() {
-		return ctrl.Result{}, nil
+	if name == "" {
+		var list djangov1alpha1.DjangoMigrateList
+		if err := r.List(ctx, &list, client.InNamespace(dm.Namespace)); err != nil {
+			return nil, err
+		}
+		if target := previousMigration(list.Items, dm.Name); target != nil {
+			return target, nil
+		}
+		return nil, &permanentError{
+			reason: ReasonRollbackTargetNotFound,
+			err:    errors.New("no applied DjangoMigrate to roll back"),
+		}
 	}
-	pod, err := r.Pods.FindDjangoPod(ctx, req.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err
+
+	var target djangov1alpha1.DjangoMigrate
+	if err := r.Get(ctx, client.ObjectKey{Namespace: dm.Namespace, Name: name}, &target); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &permanentError{reason: ReasonRollbackTargetNotFound, err: err}
+		}
+		return nil, err
 	}
-	if pod == nil {
-		logger.Info("no django-server pod found; retrying shortly")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	if target.Status.Applied.IsZero() || target.Status.PreApply == nil {
+		return nil, &permanentError{
+			reason: ReasonRollbackTargetNotFound,
+			err:    fmt.Errorf("DjangoMigrate %s has no recorded pre-apply state to roll back to", name),
+		}
 	}
-	// Build the command
+	return &target, nil
+}
+
+// previousMigration returns the most recently applied DjangoMigrate of items that can be
+// rolled back, ignoring self and other rollbacks.
+func previousMigration(items []djangov1alpha1.DjangoMigrate, self string) *djangov1alpha1.DjangoMigrate {
+	var latest *djangov1alpha1.DjangoMigrate
+	for i := range items {
+		dm := &items[i]
+		if dm.Name == self || dm.Spec.RollbackTo != "" || dm.Status.Applied.IsZero() || dm.Status.PreApply == nil {
+			continue
+		}
+		if latest == nil || latest.Status.Applied.Before(dm.Status.Applied) {
+			latest = dm
+		}
+	}
+	return latest
+}
+
+// migrateCommand builds the migrate command of dm.
+func migrateCommand(dm *djangov1alpha1.DjangoMigrate) []string {
 	shellCmd := []string{
 		"python", "manage.py", "migrate", "--noinput",
 	}
@@ -80,45 +187,200 @@ func (r *DjangoMigrateReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if dm.Spec.Migration != "" {
 		shellCmd = append(shellCmd, dm.Spec.Migration)
 	}
-	// Exec command
-	if err := r.Pods.ExecInPod(ctx, pod, shellCmd); err != nil {
-		logger.Error(err, "failed to exec migrate command", shellCmd, "pod", pod.Name)
-		return ctrl.Result{}, err
+	return shellCmd
+}
+
+// preApplyRecorded reports whether the migration state of dm was recorded for its current spec.
+func preApplyRecorded(dm *djangov1alpha1.DjangoMigrate) bool {
+	return dm.Status.PreApply != nil && dm.Status.PreApply.Generation == dm.Generation
+}
+
+// showMigrationsCommand builds the command listing the migrations of the apps dm migrates:
+// its app, the apps being rolled back, or every app.
+func showMigrationsCommand(dm *djangov1alpha1.DjangoMigrate, targets []djangov1alpha1.AppMigrationState) []string {
+	shellCmd := []string{
+		"python", "manage.py", "showmigrations", "--list", "--no-color",
+	}
+	if dm.Spec.App != "" {
+		shellCmd = append(shellCmd, dm.Spec.App)
+	}
+	for _, t := range targets {
+		shellCmd = append(shellCmd, t.App)
 	}
+	return shellCmd
+}
 
-	// Update status.Applied
-	dm.Status.Applied = metav1.Now()
-	if err := r.Status().Update(ctx, &dm); err != nil {
-		return ctrl.Result{}, err
+// recordPreApply stores the migration state printed by showMigrationsCommand. When every
+// app was listed only those with unapplied migrations are kept, as migrate leaves the
+// others alone. dm is left Pending until the migrate runs.
+func recordPreApply(dm *djangov1alpha1.DjangoMigrate, stdout string, now metav1.Time) {
+	all := dm.Spec.App == "" && dm.Spec.RollbackTo == ""
+	var apps []djangov1alpha1.AppMigrationState
+	for _, app := range parseShowMigrations(stdout) {
+		if all && app.pending == 0 {
+			continue
+		}
+		apps = append(apps, djangov1alpha1.AppMigrationState{App: app.name, Migration: app.applied})
 	}
+	dm.Status.PreApply = &djangov1alpha1.MigrationState{
+		Generation: dm.Generation,
+		RecordedAt: now,
+		Apps:       apps,
+	}
+	st := &dm.Status.CommandStatus
+	markPending(st, dm.Generation, ReasonPreApplyRecorded, msgPreApplyRecorded)
+	// The apply gets a fresh set of attempts
+	st.Attempts = 0
+}
 
-	logger.Info("Migration applied", "migrate", dm.Name)
+// appMigrations summarises the showmigrations listing of one app.
+type appMigrations struct {
+	name string
+	// applied is the last applied migration, MigrationZero when there is none
+	applied string
+	pending int
+}
+
+// parseShowMigrations parses the output of showmigrations --list: every app label on its
+// own line, followed by its migrations as indented "[X] name" or "[ ] name" lines.
+func parseShowMigrations(stdout string) []appMigrations {
+	var apps []appMigrations
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		switch {
+		case line == "":
+		case trimmed == line:
+			apps = append(apps, appMigrations{name: line, applied: djangov1alpha1.MigrationZero})
+		case len(apps) == 0 || len(trimmed) < 4 || trimmed[0] != '[' || trimmed[2] != ']':
+			// "(no migrations)" and anything unexpected
+		default:
+			fields := strings.Fields(trimmed[3:])
+			if len(fields) == 0 {
+				continue
+			}
+			app := &apps[len(apps)-1]
+			if trimmed[1] == 'X' {
+				app.applied = fields[0]
+			} else {
+				app.pending++
+			}
+		}
+	}
+	return apps
+}
+
+// rollbackScript migrates every target app back to its recorded migration, or prints the
+// plan of doing so in the format of migrate --plan, from JSON parameters read on stdin.
+const rollbackScript = `
+import json, sys
+from django.core.management import call_command
+from django.db import connection
+from django.db.migrations.executor import MigrationExecutor
+params = json.load(sys.stdin)
+if params["plan"]:
+    targets = [(t["app"], None if t["migration"] == "zero" else t["migration"]) for t in params["targets"]]
+    print("Planned operations:")
+    for migration, _ in MigrationExecutor(connection).migration_plan(targets):
+        print("%s.%s" % (migration.app_label, migration.name))
+else:
+    for t in params["targets"]:
+        call_command("migrate", t["app"], t["migration"], interactive=False, fake=params["fake"])
+`
+
+// rollbackParams is the JSON document rollbackScript reads on stdin.
+type rollbackParams struct {
+	Targets []djangov1alpha1.AppMigrationState `json:"targets"`
+	Plan    bool                               `json:"plan"`
+	Fake    bool                               `json:"fake"`
+}
 
-	// keep only the most-recent DjangoMigrate objects
-	migrateGVK := djangov1alpha1.GroupVersion.WithKind("DjangoMigrate")
-	if err := pruneOldCRs(r.Client, ctx, migrateGVK, req.Namespace, r.KeepCRs); err != nil {
-		return ctrl.Result{}, err
+// rollbackCommand builds the command migrating the apps of dm back to targets.
+func rollbackCommand(dm *djangov1alpha1.DjangoMigrate, targets []djangov1alpha1.AppMigrationState, plan bool) (ExecRequest, error) {
+	stdin, err := json.Marshal(rollbackParams{Targets: targets, Plan: plan, Fake: dm.Spec.Fake})
+	if err != nil {
+		return ExecRequest{}, err
+	}
+	return ExecRequest{
+		Command: []string{"python", "manage.py", "shell", "-c", rollbackScript},
+		Stdin:   stdin,
+	}, nil
+}
+
+// planning reports whether dm is in Plan mode and its plan has not been approved yet.
+func planning(dm *djangov1alpha1.DjangoMigrate) bool {
+	return dm.Spec.Mode == djangov1alpha1.MigrationModePlan &&
+		dm.Annotations[djangov1alpha1.MigrationApprovedAnnotation] != "true"
+}
+
+// planCommand builds the command listing what the migrate command of dm would do.
+func planCommand(dm *djangov1alpha1.DjangoMigrate) []string {
+	shellCmd := []string{
+		"python", "manage.py", "migrate", "--plan", "--no-color",
+	}
+	if dm.Spec.App != "" {
+		shellCmd = append(shellCmd, dm.Spec.App)
+	}
+	if dm.Spec.Migration != "" {
+		shellCmd = append(shellCmd, dm.Spec.Migration)
 	}
+	return shellCmd
+}
 
-	return ctrl.Result{}, nil
+// recordPlan stores the plan printed by planCommand and leaves dm waiting for approval.
+func recordPlan(dm *djangov1alpha1.DjangoMigrate, stdout string, now metav1.Time) {
+	pending := parseMigrationPlan(stdout)
+	dm.Status.Plan = &djangov1alpha1.MigrationPlan{
+		Generation: dm.Generation,
+		PlannedAt:  now,
+		Pending:    pending,
+	}
+	count := 0
+	for _, app := range pending {
+		count += len(app.Migrations)
+	}
+	st := &dm.Status.CommandStatus
+	markPending(st, dm.Generation, ReasonAwaitingApproval, fmt.Sprintf(
+		"%d pending migration(s); annotate with %s=true or set mode to Apply to apply them",
+		count, djangov1alpha1.MigrationApprovedAnnotation))
+	// The apply gets a fresh set of attempts
+	st.Attempts = 0
+}
 
+// parseMigrationPlan parses the output of migrate --plan: every migration is printed on
+// its own line as app_label.migration_name, followed by its indented operations.
+func parseMigrationPlan(stdout string) []djangov1alpha1.AppMigrations {
+	var plan []djangov1alpha1.AppMigrations
+	index := map[string]int{}
+	for _, line := range strings.Split(stdout, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || line == "Planned operations:" || strings.TrimLeft(line, " \t") != line {
+			continue
+		}
+		app, name, ok := strings.Cut(line, ".")
+		if !ok || app == "" || name == "" || strings.ContainsAny(app, " \t") {
+			continue
+		}
+		i, seen := index[app]
+		if !seen {
+			i = len(plan)
+			index[app] = i
+			plan = append(plan, djangov1alpha1.AppMigrations{App: app})
+		}
+		plan[i].Migrations = append(plan[i].Migrations, name)
+	}
+	return plan
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DjangoMigrateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// initialize REST config & clientset
-	RestCFG := mgr.GetConfig()
-	cs, err := kubernetes.NewForConfig(RestCFG)
-	if err != nil {
+	// wire in the real PodRunners
+	var err error
+	if r.Pods, r.Jobs, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
 		return err
 	}
-
-	// wire in the real PodRunner
-	r.Pods = DjangoPodRunner{
-		Client:    r.Client,
-		RESTCfg:   RestCFG,
-		Clientset: cs,
-		Label:     r.DjangoPodlabel,
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangomigrate-controller")
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
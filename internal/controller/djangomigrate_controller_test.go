@@ -18,11 +18,15 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -71,23 +75,364 @@ var _ = Describe("DjangoMigrate Controller", func() {
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
+			recorder := record.NewFakeRecorder(10)
 			controllerReconciler := &DjangoMigrateReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
-				Pods:   testPodRunner{},
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Pods:     testPodRunner{},
+				Recorder: recorder,
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
+			By("Verifying the pre-apply state is recorded first")
+			updated := &djangov1alpha1.DjangoMigrate{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.PreApply).NotTo(BeNil())
+			Expect(updated.Status.Applied).To(BeNil())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhasePending))
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(HavePrefix("Normal " + EventPreApplyRecorded)))
+			Expect(events).NotTo(ContainElement(HavePrefix("Normal " + EventExecSucceeded)))
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
 			By("Verifying the status.applied timestamp is set")
 			// Re-fetch the resource
-			updated := &djangov1alpha1.DjangoMigrate{}
 			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
 
 			// Ensure Status.Created is non-zero
 			Expect(updated.Status.Applied.IsZero()).To(BeFalse(), "expected Status.Applied to be set")
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(1))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, djangov1alpha1.ConditionReady)).To(BeTrue())
+			Expect(updated.Status.Output).NotTo(BeNil())
+			Expect(updated.Status.Output.ExitCode).To(BeZero())
+			Expect(updated.Status.Output.Stdout).To(Equal("OK"))
+		})
+	})
+
+	Context("When the migration keeps failing", func() {
+		const resourceName = "test-failing-migration"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a DjangoMigrate with a retry policy")
+			resource := &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: djangov1alpha1.DjangoMigrateSpec{
+					CommandOptions: djangov1alpha1.CommandOptions{
+						RetryPolicy: &djangov1alpha1.RetryPolicy{
+							MaxAttempts: 2,
+							BackoffBase: &metav1.Duration{Duration: time.Millisecond},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &djangov1alpha1.DjangoMigrate{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should back off and stop after maxAttempts", func() {
+			controllerReconciler := &DjangoMigrateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   failingPodRunner{},
+			}
+			updated := &djangov1alpha1.DjangoMigrate{}
+
+			By("failing the first attempt")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhasePending))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(1))
+			Expect(updated.Status.LastError).NotTo(BeEmpty())
+			Expect(updated.Status.Output.ExitCode).To(BeEquivalentTo(1))
+
+			By("giving up after the second attempt")
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseFailed))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(2))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, djangov1alpha1.ConditionFailed)).To(BeTrue())
+
+			By("not running again once Failed")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(2))
+		})
+	})
+
+	Context("When parsing migrate --plan", func() {
+		It("should group the planned migrations per app", func() {
+			Expect(parseMigrationPlan(samplePlanOutput)).To(Equal([]djangov1alpha1.AppMigrations{
+				{App: "shop", Migrations: []string{"0002_order_status", "0003_backfill_status"}},
+				{App: "accounts", Migrations: []string{"0007_profile"}},
+			}))
+			Expect(parseMigrationPlan("Planned operations:\n  No planned migration operations.\n")).To(BeEmpty())
+		})
+	})
+
+	Context("When parsing showmigrations --list", func() {
+		It("should find the last applied migration of every app", func() {
+			Expect(parseShowMigrations(sampleShowMigrationsOutput)).To(Equal([]appMigrations{
+				{name: "accounts", applied: "zero", pending: 1},
+				{name: "admin", applied: "0002_logentry_remove_auto_add"},
+				{name: "shop", applied: "0001_initial", pending: 2},
+				{name: "sessions", applied: "zero"},
+			}))
+		})
+
+		It("should only record the apps a full migrate touches", func() {
+			dm := &djangov1alpha1.DjangoMigrate{}
+			dm.Status.Attempts = 1
+			recordPreApply(dm, sampleShowMigrationsOutput, metav1.Now())
+			Expect(dm.Status.PreApply.Apps).To(Equal([]djangov1alpha1.AppMigrationState{
+				{App: "accounts", Migration: "zero"},
+				{App: "shop", Migration: "0001_initial"},
+			}))
+			Expect(dm.Status.Phase).To(Equal(djangov1alpha1.PhasePending))
+			Expect(dm.Status.Attempts).To(BeZero())
+			cond := meta.FindStatusCondition(dm.Status.Conditions, djangov1alpha1.ConditionProgressing)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonPreApplyRecorded))
+
+			dm.Spec.App = "admin"
+			recordPreApply(dm, sampleShowMigrationsOutput, metav1.Now())
+			Expect(dm.Status.PreApply.Apps).To(HaveLen(4))
+		})
+	})
+
+	Context("When choosing the previous migration", func() {
+		It("should pick the most recently applied one that is not a rollback", func() {
+			applied := func(name, rollbackTo string, minutes int) djangov1alpha1.DjangoMigrate {
+				dm := djangov1alpha1.DjangoMigrate{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				dm.Spec.RollbackTo = rollbackTo
+				if minutes > 0 {
+					at := metav1.NewTime(time.Unix(int64(minutes)*60, 0))
+					dm.Status.Applied = &at
+					dm.Status.PreApply = &djangov1alpha1.MigrationState{}
+				}
+				return dm
+			}
+			items := []djangov1alpha1.DjangoMigrate{
+				applied("first", "", 1),
+				applied("second", "", 2),
+				applied("undo-first", "first", 3),
+				applied("pending", "", 0),
+				applied("self", "", 4),
+			}
+			Expect(previousMigration(items, "self").Name).To(Equal("second"))
+			Expect(previousMigration(items[3:4], "self")).To(BeNil())
+		})
+	})
+
+	Context("When rolling back migrations", func() {
+		ctx := context.Background()
+
+		release := types.NamespacedName{Name: "test-release", Namespace: "default"}
+		undo := types.NamespacedName{Name: "test-undo", Namespace: "default"}
+		missing := types.NamespacedName{Name: "test-undo-missing", Namespace: "default"}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{Name: release.Name, Namespace: release.Namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{Name: undo.Name, Namespace: undo.Namespace},
+				Spec:       djangov1alpha1.DjangoMigrateSpec{RollbackTo: djangov1alpha1.MigrationRollbackPrevious},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{Name: missing.Name, Namespace: missing.Namespace},
+				Spec:       djangov1alpha1.DjangoMigrateSpec{RollbackTo: "does-not-exist"},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			for _, key := range []types.NamespacedName{release, undo, missing} {
+				resource := &djangov1alpha1.DjangoMigrate{}
+				Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should migrate the apps back to their pre-apply state", func() {
+			runner := &recordingPodRunner{stdout: sampleShowMigrationsOutput}
+			controllerReconciler := &DjangoMigrateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+			updated := &djangov1alpha1.DjangoMigrate{}
+
+			By("applying the release")
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: release})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(k8sClient.Get(ctx, release, updated)).To(Succeed())
+			Expect(updated.Status.Applied).NotTo(BeNil())
+			Expect(updated.Status.PreApply.Apps).To(HaveLen(2))
+
+			By("rolling back the previous migration")
+			runner.requests = nil
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: undo})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(runner.requests).To(HaveLen(2))
+			Expect(runner.requests[0].Command).To(Equal([]string{
+				"python", "manage.py", "showmigrations", "--list", "--no-color", "accounts", "shop",
+			}))
+			Expect(runner.requests[1].Command).To(Equal([]string{"python", "manage.py", "shell", "-c", rollbackScript}))
+			var params rollbackParams
+			Expect(json.Unmarshal(runner.requests[1].Stdin, &params)).To(Succeed())
+			Expect(params.Plan).To(BeFalse())
+			Expect(params.Targets).To(Equal([]djangov1alpha1.AppMigrationState{
+				{App: "accounts", Migration: "zero"},
+				{App: "shop", Migration: "0001_initial"},
+			}))
+			Expect(k8sClient.Get(ctx, undo, updated)).To(Succeed())
+			Expect(updated.Status.RollbackOf).To(Equal(release.Name))
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+		})
+
+		It("should fail when the target does not exist", func() {
+			runner := &recordingPodRunner{}
+			controllerReconciler := &DjangoMigrateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: missing})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(BeEmpty())
+			updated := &djangov1alpha1.DjangoMigrate{}
+			Expect(k8sClient.Get(ctx, missing, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseFailed))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, djangov1alpha1.ConditionFailed)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonRollbackTargetNotFound))
+		})
+	})
+
+	Context("When planning migrations", func() {
+		const resourceName = "test-plan"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: djangov1alpha1.DjangoMigrateSpec{Mode: djangov1alpha1.MigrationModePlan},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &djangov1alpha1.DjangoMigrate{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should only apply the plan once approved", func() {
+			runner := &recordingPodRunner{stdout: samplePlanOutput}
+			controllerReconciler := &DjangoMigrateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+			updated := &djangov1alpha1.DjangoMigrate{}
+
+			By("planning")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(HaveLen(1))
+			Expect(runner.requests[0].Command).To(ContainElement("--plan"))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Applied).To(BeNil())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhasePending))
+			Expect(updated.Status.Plan).NotTo(BeNil())
+			Expect(updated.Status.Plan.Pending).To(HaveLen(2))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, djangov1alpha1.ConditionProgressing)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonAwaitingApproval))
+
+			By("waiting for approval")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(HaveLen(1))
+
+			By("applying once approved")
+			updated.Annotations = map[string]string{djangov1alpha1.MigrationApprovedAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+			for range 2 {
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			Expect(runner.requests).To(HaveLen(3))
+			Expect(runner.requests[1].Command).To(ContainElement("showmigrations"))
+			Expect(runner.requests[2].Command).To(Equal([]string{"python", "manage.py", "migrate", "--noinput"}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Applied).NotTo(BeNil())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
 		})
 	})
 })
+
+// samplePlanOutput is what migrate --plan prints for three pending migrations of two apps.
+const samplePlanOutput = `Planned operations:
+shop.0002_order_status
+    Add field status to order
+shop.0003_backfill_status
+    Raw Python operation
+accounts.0007_profile
+    Create model Profile
+`
+
+// sampleShowMigrationsOutput is what showmigrations --list prints with pending migrations in two apps.
+const sampleShowMigrationsOutput = `accounts
+ [ ] 0001_initial
+admin
+ [X] 0001_initial
+ [X] 0002_logentry_remove_auto_add
+shop
+ [X] 0001_initial
+ [ ] 0002_order_status
+ [ ] 0003_backfill_status
+sessions
+ (no migrations)
+`
@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// pipelineLabel is set on every run to the name of the DjangoPipeline that created it.
+	pipelineLabel = "django.djangooperator/pipeline"
+
+	// ReasonStepFailed is set on a DjangoPipeline once one of its steps failed
+	ReasonStepFailed = "StepFailed"
+)
+
+// DjangoPipelineReconciler reconciles a DjangoPipeline object
+type DjangoPipelineReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangopipelines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangopipelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=django.djangooperator,resources=djangopipelines/finalizers,verbs=update
+
+// Reconcile walks the steps of a pipeline in order. Finished steps are taken from the
+// status, so a run pruned or deleted afterwards is not run again. The first unfinished
+// step gets its run created, or its progress recorded, and the walk stops there until
+// the run finishes and triggers the next reconcile.
+func (r *DjangoPipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	var p djangov1alpha1.DjangoPipeline
+	if err := r.Get(ctx, req.NamespacedName, &p); err != nil {
+		if errors.IsNotFound(err) {
+			// CR deleted, its runs are garbage collected through their owner reference
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	before := p.Status.DeepCopy()
+	steps := pipelineSteps(&p)
+
+	var failed []string
+	p.Status.CurrentStep = ""
+	for i := range p.Spec.Steps {
+		step, st := &p.Spec.Steps[i], &steps[i]
+		halted := len(failed) > 0 && p.Spec.FailurePolicy != djangov1alpha1.PipelineContinueOnError
+		if halted && st.Phase == djangov1alpha1.StepPending {
+			st.Phase = djangov1alpha1.StepSkipped
+		}
+		if st.Phase == djangov1alpha1.StepFailed {
+			failed = append(failed, step.Name)
+		}
+		if st.Phase != djangov1alpha1.StepPending && st.Phase != djangov1alpha1.StepRunning {
+			continue
+		}
+
+		p.Status.CurrentStep = step.Name
+		run, err := stepRun(&p, step)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		err = r.Get(ctx, client.ObjectKeyFromObject(run), run)
+		if errors.IsNotFound(err) {
+			if err := r.createRun(ctx, &p, run); err != nil {
+				return ctrl.Result{}, err
+			}
+			logger.Info("Created pipeline step", "step", step.Name, "kind", st.Kind, "name", run.GetName())
+			st.Phase, st.Run = djangov1alpha1.StepPending, run.GetName()
+			st.StartTime = ptr.To(metav1.Now())
+			break
+		}
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		cs := run.GetCommandStatus()
+		st.Run = run.GetName()
+		if st.StartTime == nil {
+			st.StartTime = ptr.To(metav1.Now())
+		}
+		switch cs.Phase {
+		case djangov1alpha1.PhaseSucceeded:
+			st.Phase, st.CompletionTime, st.Message = djangov1alpha1.StepSucceeded, cs.CompletionTime, ""
+			continue
+		case djangov1alpha1.PhaseFailed:
+			st.Phase, st.CompletionTime, st.Message = djangov1alpha1.StepFailed, cs.CompletionTime, cs.LastError
+			failed = append(failed, step.Name)
+			r.event(&p, corev1.EventTypeWarning, "Step %s failed: %s", step.Name, cs.LastError)
+			continue
+		case djangov1alpha1.PhaseRunning:
+			st.Phase = djangov1alpha1.StepRunning
+		default:
+			st.Phase, st.Message = djangov1alpha1.StepPending, cs.LastError
+		}
+		break
+	}
+	p.Status.Steps = steps
+
+	switch {
+	case p.Status.CurrentStep != "":
+		setPipelinePhase(&p, djangov1alpha1.PhaseRunning, ReasonRunning, "running step "+p.Status.CurrentStep)
+	case len(failed) > 0:
+		setPipelinePhase(&p, djangov1alpha1.PhaseFailed, ReasonStepFailed, "failed steps: "+strings.Join(failed, ", "))
+	default:
+		setPipelinePhase(&p, djangov1alpha1.PhaseSucceeded, ReasonSucceeded, "all steps succeeded")
+	}
+	if before.Phase != p.Status.Phase && p.Status.CompletionTime != nil {
+		r.event(&p, corev1.EventTypeNormal, "Pipeline %s", p.Status.Phase)
+	}
+	if equality.Semantic.DeepEqual(before, &p.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, &p)
+}
+
+// createRun creates run, owned by p, and records an event for it on p.
+func (r *DjangoPipelineReconciler) createRun(ctx context.Context, p *djangov1alpha1.DjangoPipeline, run CommandObject) error {
+	if err := controllerutil.SetControllerReference(p, run, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, run); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	gvk, err := r.GroupVersionKindFor(run)
+	if err != nil {
+		return err
+	}
+	r.event(p, corev1.EventTypeNormal, "Created %s %s", gvk.Kind, run.GetName())
+	return nil
+}
+
+func (r *DjangoPipelineReconciler) event(p *djangov1alpha1.DjangoPipeline, eventtype, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(p, eventtype, EventPipelineStep, messageFmt, args...)
+	}
+}
+
+// pipelineSteps returns the status of every step of p, in spec order, starting from
+// the recorded one.
+func pipelineSteps(p *djangov1alpha1.DjangoPipeline) []djangov1alpha1.PipelineStepStatus {
+	recorded := map[string]djangov1alpha1.PipelineStepStatus{}
+	for _, st := range p.Status.Steps {
+		recorded[st.Name] = st
+	}
+	steps := make([]djangov1alpha1.PipelineStepStatus, len(p.Spec.Steps))
+	for i, step := range p.Spec.Steps {
+		st, ok := recorded[step.Name]
+		if !ok {
+			st = djangov1alpha1.PipelineStepStatus{Name: step.Name, Phase: djangov1alpha1.StepPending}
+		}
+		st.Kind = stepKind(&step)
+		steps[i] = *st.DeepCopy()
+	}
+	return steps
+}
+
+// stepKind returns the kind of the run of step.
+func stepKind(step *djangov1alpha1.DjangoPipelineStep) string {
+	switch {
+	case step.Migrate != nil:
+		return "DjangoMigrate"
+	case step.Static != nil:
+		return "DjangoStatic"
+	case step.Celery != nil:
+		return "DjangoCelery"
+	case step.User != nil:
+		return "DjangoUser"
+	case step.Command != nil:
+		return "DjangoCommand"
+	}
+	return ""
+}
+
+// stepRun builds the run of step from its spec.
+func stepRun(p *djangov1alpha1.DjangoPipeline, step *djangov1alpha1.DjangoPipelineStep) (CommandObject, error) {
+	meta := metav1.ObjectMeta{
+		Name:      p.Name + "-" + step.Name,
+		Namespace: p.Namespace,
+		Labels:    map[string]string{pipelineLabel: p.Name},
+	}
+	switch {
+	case step.Migrate != nil:
+		return &djangov1alpha1.DjangoMigrate{ObjectMeta: meta, Spec: *step.Migrate.DeepCopy()}, nil
+	case step.Static != nil:
+		return &djangov1alpha1.DjangoStatic{ObjectMeta: meta, Spec: *step.Static.DeepCopy()}, nil
+	case step.Celery != nil:
+		return &djangov1alpha1.DjangoCelery{ObjectMeta: meta, Spec: *step.Celery.DeepCopy()}, nil
+	case step.User != nil:
+		return &djangov1alpha1.DjangoUser{ObjectMeta: meta, Spec: *step.User.DeepCopy()}, nil
+	case step.Command != nil:
+		return &djangov1alpha1.DjangoCommand{ObjectMeta: meta, Spec: *step.Command.DeepCopy()}, nil
+	default:
+		return nil, fmt.Errorf("step %s of DjangoPipeline %s sets no run", step.Name, p.Name)
+	}
+}
+
+// setPipelinePhase sets the phase of p and its Ready, Progressing and Failed conditions.
+func setPipelinePhase(p *djangov1alpha1.DjangoPipeline, phase djangov1alpha1.CommandPhase, reason, msg string) {
+	status := func(b bool) metav1.ConditionStatus {
+		if b {
+			return metav1.ConditionTrue
+		}
+		return metav1.ConditionFalse
+	}
+	for condType, s := range map[string]metav1.ConditionStatus{
+		djangov1alpha1.ConditionReady:       status(phase == djangov1alpha1.PhaseSucceeded),
+		djangov1alpha1.ConditionProgressing: status(phase == djangov1alpha1.PhaseRunning),
+		djangov1alpha1.ConditionFailed:      status(phase == djangov1alpha1.PhaseFailed),
+	} {
+		meta.SetStatusCondition(&p.Status.Conditions, metav1.Condition{
+			Type:               condType,
+			Status:             s,
+			ObservedGeneration: p.Generation,
+			Reason:             reason,
+			Message:            msg,
+		})
+	}
+	if phase == djangov1alpha1.PhaseRunning {
+		p.Status.CompletionTime = nil
+	} else if p.Status.Phase != phase || p.Status.CompletionTime == nil {
+		p.Status.CompletionTime = ptr.To(metav1.Now())
+	}
+	p.Status.Phase = phase
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DjangoPipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangopipeline-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&djangov1alpha1.DjangoPipeline{}).
+		Owns(&djangov1alpha1.DjangoMigrate{}).
+		Owns(&djangov1alpha1.DjangoStatic{}).
+		Owns(&djangov1alpha1.DjangoCelery{}).
+		Owns(&djangov1alpha1.DjangoUser{}).
+		Owns(&djangov1alpha1.DjangoCommand{}).
+		Named("djangopipeline").
+		Complete(r)
+}
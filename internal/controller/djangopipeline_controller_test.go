@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("DjangoPipeline Controller", func() {
+	Context("When building the runs of the steps", func() {
+		p := &djangov1alpha1.DjangoPipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: "release", Namespace: "default"},
+			Spec: djangov1alpha1.DjangoPipelineSpec{Steps: []djangov1alpha1.DjangoPipelineStep{
+				{Name: "migrate", Migrate: &djangov1alpha1.DjangoMigrateSpec{App: "shop"}},
+				{Name: "admin", User: &djangov1alpha1.DjangoUserSpec{Username: "admin"}},
+			}},
+		}
+
+		It("should name runs after the pipeline and the step", func() {
+			run, err := stepRun(p, &p.Spec.Steps[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run).To(BeAssignableToTypeOf(&djangov1alpha1.DjangoMigrate{}))
+			Expect(run.GetName()).To(Equal("release-migrate"))
+			Expect(run.GetLabels()).To(HaveKeyWithValue(pipelineLabel, "release"))
+			Expect(run.(*djangov1alpha1.DjangoMigrate).Spec.App).To(Equal("shop"))
+
+			run, err = stepRun(p, &p.Spec.Steps[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(run.(*djangov1alpha1.DjangoUser).Spec.Username).To(Equal("admin"))
+
+			_, err = stepRun(p, &djangov1alpha1.DjangoPipelineStep{Name: "empty"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should keep the recorded status of every step", func() {
+			recorded := p.DeepCopy()
+			recorded.Status.Steps = []djangov1alpha1.PipelineStepStatus{
+				{Name: "migrate", Phase: djangov1alpha1.StepSucceeded, Run: "release-migrate"},
+			}
+			steps := pipelineSteps(recorded)
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Phase).To(Equal(djangov1alpha1.StepSucceeded))
+			Expect(steps[0].Kind).To(Equal("DjangoMigrate"))
+			Expect(steps[1].Name).To(Equal("admin"))
+			Expect(steps[1].Kind).To(Equal("DjangoUser"))
+			Expect(steps[1].Phase).To(Equal(djangov1alpha1.StepPending))
+		})
+	})
+
+	Context("When running a pipeline", func() {
+		ctx := context.Background()
+		name := types.NamespacedName{Name: "release", Namespace: "default"}
+
+		create := func(policy djangov1alpha1.PipelineFailurePolicy) {
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoPipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+				Spec: djangov1alpha1.DjangoPipelineSpec{
+					FailurePolicy: policy,
+					Steps: []djangov1alpha1.DjangoPipelineStep{
+						{Name: "migrate", Migrate: &djangov1alpha1.DjangoMigrateSpec{}},
+						{Name: "static", Static: &djangov1alpha1.DjangoStaticSpec{}},
+						{Name: "purge", Celery: &djangov1alpha1.DjangoCelerySpec{App: "shop"}},
+					},
+				},
+			})).To(Succeed())
+		}
+		reconcilePipeline := func() *djangov1alpha1.DjangoPipeline {
+			controllerReconciler := &DjangoPipelineReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+			p := &djangov1alpha1.DjangoPipeline{}
+			Expect(k8sClient.Get(ctx, name, p)).To(Succeed())
+			return p
+		}
+		finish := func(run CommandObject, phase djangov1alpha1.CommandPhase) {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(run), run)).To(Succeed())
+			st := run.GetCommandStatus()
+			st.Phase = phase
+			now := metav1.Now()
+			st.CompletionTime = &now
+			if phase == djangov1alpha1.PhaseFailed {
+				st.LastError = "boom"
+			}
+			Expect(k8sClient.Status().Update(ctx, run)).To(Succeed())
+		}
+		runExists := func(run client.Object) bool {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(run), run)
+			if errors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return true
+		}
+		migrate := &djangov1alpha1.DjangoMigrate{ObjectMeta: metav1.ObjectMeta{Name: "release-migrate", Namespace: "default"}}
+		static := &djangov1alpha1.DjangoStatic{ObjectMeta: metav1.ObjectMeta{Name: "release-static", Namespace: "default"}}
+		celery := &djangov1alpha1.DjangoCelery{ObjectMeta: metav1.ObjectMeta{Name: "release-purge", Namespace: "default"}}
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoPipeline{
+				ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			})).To(Succeed())
+			// envtest runs no garbage collector
+			for _, run := range []client.Object{migrate, static, celery} {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, run))).To(Succeed())
+			}
+		})
+
+		It("should run the steps in order and stop on failure", func() {
+			create(djangov1alpha1.PipelineStopOnFailure)
+
+			By("starting with the first step only")
+			p := reconcilePipeline()
+			Expect(p.Status.Phase).To(Equal(djangov1alpha1.PhaseRunning))
+			Expect(p.Status.CurrentStep).To(Equal("migrate"))
+			Expect(runExists(migrate)).To(BeTrue())
+			Expect(migrate.OwnerReferences).To(HaveLen(1))
+			Expect(runExists(static)).To(BeFalse())
+
+			By("moving on once it succeeds")
+			finish(migrate, djangov1alpha1.PhaseSucceeded)
+			p = reconcilePipeline()
+			Expect(p.Status.CurrentStep).To(Equal("static"))
+			Expect(p.Status.Steps[0].Phase).To(Equal(djangov1alpha1.StepSucceeded))
+			Expect(runExists(static)).To(BeTrue())
+
+			By("skipping the remaining steps once one fails")
+			finish(static, djangov1alpha1.PhaseFailed)
+			p = reconcilePipeline()
+			Expect(p.Status.Phase).To(Equal(djangov1alpha1.PhaseFailed))
+			Expect(p.Status.CurrentStep).To(BeEmpty())
+			Expect(p.Status.Steps[1].Phase).To(Equal(djangov1alpha1.StepFailed))
+			Expect(p.Status.Steps[1].Message).To(Equal("boom"))
+			Expect(p.Status.Steps[2].Phase).To(Equal(djangov1alpha1.StepSkipped))
+			Expect(runExists(celery)).To(BeFalse())
+			Expect(meta.IsStatusConditionTrue(p.Status.Conditions, djangov1alpha1.ConditionFailed)).To(BeTrue())
+			Expect(p.Status.CompletionTime).NotTo(BeNil())
+		})
+
+		It("should run every step when continuing on error", func() {
+			create(djangov1alpha1.PipelineContinueOnError)
+
+			reconcilePipeline()
+			finish(migrate, djangov1alpha1.PhaseFailed)
+			p := reconcilePipeline()
+			Expect(p.Status.CurrentStep).To(Equal("static"))
+			Expect(runExists(static)).To(BeTrue())
+
+			finish(static, djangov1alpha1.PhaseSucceeded)
+			reconcilePipeline()
+			finish(celery, djangov1alpha1.PhaseSucceeded)
+			p = reconcilePipeline()
+			Expect(p.Status.Phase).To(Equal(djangov1alpha1.PhaseFailed))
+			Expect(p.Status.Steps[2].Phase).To(Equal(djangov1alpha1.StepSucceeded))
+			cond := meta.FindStatusCondition(p.Status.Conditions, djangov1alpha1.ConditionFailed)
+			Expect(cond.Reason).To(Equal(ReasonStepFailed))
+			Expect(cond.Message).To(ContainSubstring("migrate"))
+
+			By("not running finished steps again once their runs are gone")
+			Expect(k8sClient.Delete(ctx, migrate)).To(Succeed())
+			reconcilePipeline()
+			Expect(runExists(migrate)).To(BeFalse())
+		})
+	})
+})
@@ -18,25 +18,26 @@ package controller
 
 import (
 	"context"
-	"time"
 
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // DjangoStaticReconciler reconciles a DjangoStatic object
 type DjangoStaticReconciler struct {
 	client.Client
-	Scheme         *runtime.Scheme
-	Pods           PodRunner
+	Scheme *runtime.Scheme
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container      string
 	DjangoPodlabel PodLabel
-	KeepCRs        int
+	Recorder       record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=django.djangooperator,resources=djangostatics,verbs=get;list;watch;create;update;patch;delete
@@ -45,71 +46,44 @@ type DjangoStaticReconciler struct {
 
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/reconcile
 func (r *DjangoStaticReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := logf.FromContext(ctx)
-	// Fetch the DjangoUser
-	var ds djangov1alpha1.DjangoStatic
-	if err := r.Get(ctx, req.NamespacedName, &ds); err != nil {
-		if errors.IsNotFound(err) {
-			// CR deleted, nothing to do
-			return ctrl.Result{}, nil
-		}
-		return ctrl.Result{}, err
-	}
-	// Skip if already created
-	if !ds.Status.Collected.IsZero() {
-		return ctrl.Result{}, nil
-	}
-	pod, err := r.Pods.FindDjangoPod(ctx, req.Namespace)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	if pod == nil {
-		logger.Info("no django-server pod found; retrying shortly")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-	// Build the command
-	shellCmd := []string{
-		"python", "manage.py", "collectstatic", "--noinput",
-	}
-	// Exec command
-	if err := r.Pods.ExecInPod(ctx, pod, shellCmd); err != nil {
-		logger.Error(err, "failed to exec collectstatic command", shellCmd, "pod", pod.Name)
-		return ctrl.Result{}, err
-	}
-
-	// Update status.Applied
-	ds.Status.Collected = metav1.Now()
-	if err := r.Status().Update(ctx, &ds); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	logger.Info("Statics collected", "collectstatic", ds.Name)
+	return r.commands().Reconcile(ctx, req)
+}
 
-	// keep only the most-recent DjangoStatic objects
-	staticGVK := djangov1alpha1.GroupVersion.WithKind("DjangoStatic")
-	if err := pruneOldCRs(r.Client, ctx, staticGVK, req.Namespace, r.KeepCRs); err != nil {
-		return ctrl.Result{}, err
+// commands returns the shared one-shot reconciler running collectstatic for DjangoStatics.
+func (r *DjangoStaticReconciler) commands() commandReconciler[*djangov1alpha1.DjangoStatic] {
+	return commandReconciler[*djangov1alpha1.DjangoStatic]{
+		Client:    r.Client,
+		Pods:      r.Pods,
+		Jobs:      r.Jobs,
+		Runner:    r.Runner,
+		Container: r.Container,
+		Recorder:  r.Recorder,
+		Task: commandTask[*djangov1alpha1.DjangoStatic]{
+			Kind: "DjangoStatic",
+			New:  func() *djangov1alpha1.DjangoStatic { return &djangov1alpha1.DjangoStatic{} },
+			Done: func(ds *djangov1alpha1.DjangoStatic) bool { return !ds.Status.Collected.IsZero() },
+			Command: func(context.Context, *djangov1alpha1.DjangoStatic) (ExecRequest, error) {
+				return ExecRequest{Command: []string{"python", "manage.py", "collectstatic", "--noinput"}}, nil
+			},
+			Succeeded: func(ds *djangov1alpha1.DjangoStatic, _ ExecResult, now metav1.Time) string {
+				ds.Status.Collected = &now
+				return ""
+			},
+			// Migrations and collectstatic must not race each other
+			Exclusive: true,
+		},
 	}
-
-	return ctrl.Result{}, nil
-
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DjangoStaticReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// initialize REST config & clientset
-	RestCFG := mgr.GetConfig()
-	cs, err := kubernetes.NewForConfig(RestCFG)
-	if err != nil {
+	// wire in the real PodRunners
+	var err error
+	if r.Pods, r.Jobs, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
 		return err
 	}
-
-	// wire in the real PodRunner
-	r.Pods = DjangoPodRunner{
-		Client:    r.Client,
-		RESTCfg:   RestCFG,
-		Clientset: cs,
-		Label:     r.DjangoPodlabel,
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangostatic-controller")
 	}
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -84,6 +85,9 @@ var _ = Describe("DjangoStatic Controller", func() {
 
 			// Ensure Status.Created is non-zero
 			Expect(updated.Status.Collected.IsZero()).To(BeFalse(), "expected Status.Collected to be set")
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(1))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, djangov1alpha1.ConditionReady)).To(BeTrue())
 		})
 	})
 })
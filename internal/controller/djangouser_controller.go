@@ -18,6 +18,11 @@ package controller
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -27,20 +32,158 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// userFinalizer is held by DjangoUsers whose deletion policy deactivates or deletes the account.
+const userFinalizer = "django.djangooperator/user-cleanup"
+
+// defaultCleanupAttempts bounds the attempts at cleaning up a deleted DjangoUser whose
+// retry policy sets no limit, so that its finalizer cannot block the namespace forever.
+const defaultCleanupAttempts = 10
+
+// passwordSecretField indexes DjangoUsers by the name of their password Secret.
+const passwordSecretField = ".spec.passwordSecretRef.name"
+
+// fingerprintKeySecret holds, under fingerprintKeyField, the key of the password
+// fingerprints of the DjangoUsers of its namespace. The operator creates it on first use.
+const (
+	fingerprintKeySecret = "django-operator-password-fingerprint"
+	fingerprintKeyField  = "key"
 )
 
 // DjangoUserReconciler reconciles a DjangoUser object
 
 type DjangoUserReconciler struct {
 	client.Client
-	Scheme         *runtime.Scheme
-	Pods           PodRunner
+	Scheme *runtime.Scheme
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container      string
 	DjangoPodlabel PodLabel
-	KeepCRs        int
+	Recorder       record.EventRecorder
+}
+
+// createUserScript creates or updates a Django user from JSON parameters read on stdin.
+const createUserScript = `
+import json, sys
+from django.contrib.auth import get_user_model
+params = json.load(sys.stdin)
+User = get_user_model()
+u, created = User.objects.get_or_create(username=params["username"], defaults={"email": params["email"]})
+u.email = params["email"]
+u.is_superuser = params["superuser"]
+u.is_staff = params["staff"]
+u.is_active = params["active"]
+# Keep the hash, and with it the sessions of the user, unless the password changed
+if not u.check_password(params["password"]):
+    u.set_password(params["password"])
+u.save()
+`
+
+// userParams is the JSON document createUserScript reads on stdin.
+type userParams struct {
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Superuser bool   `json:"superuser"`
+	Staff     bool   `json:"staff"`
+	Active    bool   `json:"active"`
+}
+
+// cleanupUserScript deactivates or deletes a Django user named by JSON parameters read on stdin.
+const cleanupUserScript = `
+import json, sys
+from django.contrib.auth import get_user_model
+params = json.load(sys.stdin)
+users = get_user_model().objects.filter(username=params["username"])
+if params["delete"]:
+    users.delete()
+else:
+    users.update(is_active=False)
+`
+
+// userCleanupParams is the JSON document cleanupUserScript reads on stdin.
+type userCleanupParams struct {
+	Username string `json:"username"`
+	Delete   bool   `json:"delete"`
+}
+
+// cleanupUserCommand returns the command applying the deletion policy of du.
+func cleanupUserCommand(du *djangov1alpha1.DjangoUser) ([]string, []byte, error) {
+	stdin, err := json.Marshal(userCleanupParams{
+		Username: du.Spec.Username,
+		Delete:   du.Spec.DeletionPolicy == djangov1alpha1.UserDeletionDelete,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{"python", "manage.py", "shell", "-c", cleanupUserScript}, stdin, nil
+}
+
+// passwordFingerprint identifies password without revealing it: an HMAC keyed with
+// material only the operator and readers of Secrets hold cannot be brute-forced from
+// the status alone.
+func passwordFingerprint(key, password []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(password)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// fingerprintKey returns the key of the password fingerprints of namespace, generating
+// it on first use.
+func (r *DjangoUserReconciler) fingerprintKey(ctx context.Context, namespace string) ([]byte, error) {
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: fingerprintKeySecret}, &secret)
+	if err == nil {
+		key := secret.Data[fingerprintKeyField]
+		if len(key) == 0 {
+			return nil, fmt.Errorf("secret %s missing key %q", fingerprintKeySecret, fingerprintKeyField)
+		}
+		return key, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	secret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: fingerprintKeySecret, Namespace: namespace},
+		Data:       map[string][]byte{fingerprintKeyField: key},
+	}
+	// When the cache has not seen a Secret created meanwhile, AlreadyExists requeues
+	if err := r.Create(ctx, &secret); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// createUserCommand returns the command creating or updating du and the stdin payload
+// carrying its credentials.
+func createUserCommand(du *djangov1alpha1.DjangoUser, password string) ([]string, []byte, error) {
+	stdin, err := json.Marshal(userParams{
+		Username:  du.Spec.Username,
+		Email:     du.Spec.Email,
+		Password:  password,
+		Superuser: du.Spec.Superuser,
+		Staff:     ptr.Deref(du.Spec.Staff, true),
+		Active:    ptr.Deref(du.Spec.Active, true),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{"python", "manage.py", "shell", "-c", createUserScript}, stdin, nil
 }
 
 // As our operator us confined in a namespace, the role file needs to be edited manually. Nevertheless,
@@ -52,6 +195,7 @@ type DjangoUserReconciler struct {
 // +kubebuilder:rbac:groups=django.djangooperator,resources=djangousers/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
@@ -67,9 +211,18 @@ func (r *DjangoUserReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 		return ctrl.Result{}, err
 	}
-	// Skip if already created
-	if !du.Status.Created.IsZero() {
-		return ctrl.Result{}, nil
+	// Clean up the Django account if the CR is being deleted
+	if !du.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &du)
+	}
+	// Only hold a finalizer when deleting the CR has to touch the database
+	cleanup := du.Spec.DeletionPolicy == djangov1alpha1.UserDeletionDeactivate ||
+		du.Spec.DeletionPolicy == djangov1alpha1.UserDeletionDelete
+	if (cleanup && controllerutil.AddFinalizer(&du, userFinalizer)) ||
+		(!cleanup && controllerutil.RemoveFinalizer(&du, userFinalizer)) {
+		if err := r.Update(ctx, &du); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 	// Read the password from the Secret
 	var pwSecret corev1.Secret
@@ -77,6 +230,11 @@ func (r *DjangoUserReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		types.NamespacedName{Namespace: req.Namespace, Name: du.Spec.PasswordSecretRef.Name},
 		&pwSecret,
 	); err != nil {
+		if errors.IsNotFound(err) && !du.Status.Created.IsZero() {
+			// Nothing to rotate to; keep the password already applied
+			logger.Info("password secret not found; skipping", "secret", du.Spec.PasswordSecretRef.Name)
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{}, fmt.Errorf("reading password secret: %w", err)
 	}
 	raw, ok := pwSecret.Data[du.Spec.PasswordSecretRef.Key]
@@ -85,84 +243,256 @@ func (r *DjangoUserReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			du.Spec.PasswordSecretRef.Name, du.Spec.PasswordSecretRef.Key)
 	}
 	password := string(raw)
-	pod, err := r.Pods.FindDjangoPod(ctx, req.Namespace)
+	key, err := r.fingerprintKey(ctx, req.Namespace)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, fmt.Errorf("reading password fingerprint key: %w", err)
 	}
-	if pod == nil {
-		logger.Info("no django-server pod found; retrying shortly")
-		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
-	}
-	// 4) Build the command
-	pySuperuser := "False"
-	if du.Spec.Superuser {
-		pySuperuser = "True"
-	}
-	shellCmd := []string{
-		"python", "manage.py", "shell", "-c",
-		fmt.Sprintf(`
-from django.contrib.auth import get_user_model;
-User = get_user_model();
-username = '%s';
-password = '%s';
-email = '%s';
-superuser = '%s';
-u, created = User.objects.get_or_create(username=username, defaults={
-    "email": email,
-    "is_staff": True,
-    "is_superuser": superuser,
-    "is_active": True
-});
-if not created:
-    u.email = email
-    u.is_staff = True
-    u.is_superuser = superuser
-    u.is_active = True
-u.set_password(password);
-u.save()`, du.Spec.Username, password, du.Spec.Email, pySuperuser),
-	}
-
-	// Exec command
-	if err := r.Pods.ExecInPod(ctx, pod, shellCmd); err != nil {
-		logger.Error(err, "failed to exec create user command", du.Spec.Username, "pod", pod.Name)
-		return ctrl.Result{}, err
+	fingerprint := passwordFingerprint(key, raw)
+
+	// Unlike the other commands a DjangoUser is kept in sync: once done it is applied
+	// again whenever its spec or the referenced password changes. Other changes of the
+	// Secret, e.g. of its labels or other keys, leave the fingerprint alone.
+	changed := du.Status.PasswordFingerprint != fingerprint || du.Status.ObservedGeneration != du.Generation
+	if du.Status.Phase == djangov1alpha1.PhaseSucceeded || du.Status.Phase == djangov1alpha1.PhaseFailed {
+		if !changed {
+			return ctrl.Result{}, nil
+		}
+		// Start over with a fresh set of attempts
+		du.Status.Attempts = 0
+		du.Status.NextRetryTime = nil
 	}
+	return r.commands(password, fingerprint).run(ctx, &du)
+}
 
-	// Update status.Created
-	du.Status.Created = metav1.Now()
-	if err := r.Status().Update(ctx, &du); err != nil {
-		return ctrl.Result{}, err
+// commands returns the shared one-shot reconciler creating or updating the Django
+// account of a DjangoUser with the given password.
+func (r *DjangoUserReconciler) commands(password, fingerprint string) commandReconciler[*djangov1alpha1.DjangoUser] {
+	return commandReconciler[*djangov1alpha1.DjangoUser]{
+		Client:    r.Client,
+		Pods:      r.Pods,
+		Jobs:      r.Jobs,
+		Runner:    r.Runner,
+		Container: r.Container,
+		Recorder:  r.Recorder,
+		Task: commandTask[*djangov1alpha1.DjangoUser]{
+			Kind: "DjangoUser",
+			// Credentials are sent on stdin so they are neither interpolated into
+			// Python source nor visible in the process list
+			Command: func(_ context.Context, du *djangov1alpha1.DjangoUser) (ExecRequest, error) {
+				shellCmd, stdin, err := createUserCommand(du, password)
+				if err != nil {
+					return ExecRequest{}, err
+				}
+				// Recorded with the attempt, so a failing password is not retried
+				// forever but a rotated one is applied again
+				du.Status.PasswordFingerprint = fingerprint
+				return ExecRequest{Command: shellCmd, Stdin: stdin}, nil
+			},
+			Succeeded: func(du *djangov1alpha1.DjangoUser, _ ExecResult, now metav1.Time) string {
+				du.Status.Created = &now
+				return ""
+			},
+		},
 	}
+}
 
-	logger.Info("User created", "user", du.Spec.Username, "superuser", du.Spec.Superuser)
+// finalize deactivates or deletes the Django account of a DjangoUser being deleted,
+// according to its deletion policy, and then releases the CR. Cleanup is retried
+// until it succeeds, as giving up would leave the account behind.
+func (r *DjangoUserReconciler) finalize(ctx context.Context, du *djangov1alpha1.DjangoUser) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	events := commandEvents{Reader: r.Client, Recorder: r.Recorder}
+	if !controllerutil.ContainsFinalizer(du, userFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if du.Annotations[djangov1alpha1.UserSkipCleanupAnnotation] == "true" {
+		return r.skipCleanup(ctx, du, "the %s annotation is set", djangov1alpha1.UserSkipCleanupAnnotation)
+	}
+	// Nothing to clean up if the account was never created
+	if du.Spec.DeletionPolicy != djangov1alpha1.UserDeletionRetain && !du.Status.Created.IsZero() {
+		if wait := retryWait(&du.Status.CommandStatus); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+		pod, err := r.Pods.FindDjangoPod(ctx, du.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if pod == nil {
+			// Once the app is gone, e.g. with its namespace, its database usually is too
+			gone, err := r.djangoGone(ctx, du.Namespace)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if gone {
+				return r.skipCleanup(ctx, du, "no DjangoApp or Django pod is left in the namespace")
+			}
+			logger.Info("no ready django pod found; retrying user cleanup shortly")
+			markPending(&du.Status.CommandStatus, du.Generation, ReasonNoReadyPod, msgNoReadyPod)
+			events.emit(ctx, du, "DjangoUser", nil, corev1.EventTypeWarning, EventPodNotFound, msgNoReadyPod)
+			if err := r.Status().Update(ctx, du); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		err = r.cleanupUser(ctx, du, pod)
+		if jobRunning(err) {
+			return ctrl.Result{RequeueAfter: jobRequeueInterval}, nil
+		}
+		if err != nil {
+			logger.Error(err, "failed to clean up django user", "user", du.Spec.Username, "pod", pod.Name)
+			events.emit(ctx, du, "DjangoUser", pod, corev1.EventTypeWarning, EventExecFailed,
+				"Cleanup of user %s failed: %v", du.Spec.Username, err)
+			delay := handleFailure(&du.Status.CommandStatus, du.Generation, cleanupRetryPolicy(du.Spec.RetryPolicy),
+				ReasonCleanupFailed, err)
+			if du.Status.Phase == djangov1alpha1.PhaseFailed {
+				return r.skipCleanup(ctx, du, "%s", du.Status.LastError)
+			}
+			if uerr := r.Status().Update(ctx, du); uerr != nil {
+				return ctrl.Result{}, uerr
+			}
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+		events.emit(ctx, du, "DjangoUser", pod, corev1.EventTypeNormal, EventExecSucceeded,
+			"%s user %s", cleanupVerb(du.Spec.DeletionPolicy), du.Spec.Username)
+		logger.Info("User cleaned up", "user", du.Spec.Username, "policy", du.Spec.DeletionPolicy)
+	}
 
-	// keep only the most-recent DjangoUser objects
-	userGVK := djangov1alpha1.GroupVersion.WithKind("DjangoCelery")
-	if err := pruneOldCRs(r.Client, ctx, userGVK, req.Namespace, r.KeepCRs); err != nil {
+	controllerutil.RemoveFinalizer(du, userFinalizer)
+	if err := r.Update(ctx, du); err != nil {
 		return ctrl.Result{}, err
 	}
-
 	return ctrl.Result{}, nil
 }
 
+// cleanupRetryPolicy returns the retry policy of the cleanup of a DjangoUser with the
+// retry policy p: p, with at most defaultCleanupAttempts attempts unless p sets a limit.
+func cleanupRetryPolicy(p *djangov1alpha1.RetryPolicy) *djangov1alpha1.RetryPolicy {
+	policy := &djangov1alpha1.RetryPolicy{}
+	if p != nil {
+		policy = p.DeepCopy()
+	}
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaultCleanupAttempts
+	}
+	return policy
+}
+
+// skipCleanup drops the finalizer of du without applying its deletion policy, recording
+// why in a warning event.
+func (r *DjangoUserReconciler) skipCleanup(ctx context.Context, du *djangov1alpha1.DjangoUser,
+	messageFmt string, args ...interface{}) (ctrl.Result, error) {
+	reason := fmt.Sprintf(messageFmt, args...)
+	logf.FromContext(ctx).Info("Skipping user cleanup", "user", du.Spec.Username, "reason", reason)
+	commandEvents{Reader: r.Client, Recorder: r.Recorder}.emit(ctx, du, "DjangoUser", nil,
+		corev1.EventTypeWarning, EventCleanupSkipped, "Cleanup of user %s skipped: %s", du.Spec.Username, reason)
+	controllerutil.RemoveFinalizer(du, userFinalizer)
+	return ctrl.Result{}, r.Update(ctx, du)
+}
+
+// djangoGone reports whether namespace has neither a DjangoApp that is not being deleted
+// nor a Django pod, ready or not.
+func (r *DjangoUserReconciler) djangoGone(ctx context.Context, namespace string) (bool, error) {
+	var apps djangov1alpha1.DjangoAppList
+	if err := r.List(ctx, &apps, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for _, app := range apps.Items {
+		if app.DeletionTimestamp.IsZero() {
+			return false, nil
+		}
+	}
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabels(r.DjangoPodlabel)); err != nil {
+		return false, err
+	}
+	return len(pods.Items) == 0, nil
+}
+
+// cleanupUser runs the command applying the deletion policy of du in pod.
+func (r *DjangoUserReconciler) cleanupUser(ctx context.Context, du *djangov1alpha1.DjangoUser, pod *corev1.Pod) error {
+	container, err := containerFor(pod, r.Container, du.Spec.Container)
+	if err != nil {
+		return err
+	}
+	shellCmd, stdin, err := cleanupUserCommand(du)
+	if err != nil {
+		return err
+	}
+	// A cleanup already Running is resumed, e.g. its Job launched by an earlier reconcile
+	if du.Status.Phase != djangov1alpha1.PhaseRunning {
+		markRunning(&du.Status.CommandStatus, du.Generation, pod.Name)
+		if err := r.Status().Update(ctx, du); err != nil {
+			return err
+		}
+		commandEvents{Reader: r.Client, Recorder: r.Recorder}.emit(ctx, du, "DjangoUser", pod,
+			corev1.EventTypeNormal, EventExecStarted, "Cleanup of user %s started in pod %s", du.Spec.Username, pod.Name)
+	}
+	res, err := runnerFor(r.Pods, r.Jobs, r.Runner, du.Spec.Runner).ExecInPod(ctx, pod, ExecRequest{
+		Container: container,
+		Command:   shellCmd,
+		Stdin:     stdin,
+		Owner:     du.UID,
+	})
+	if jobRunning(err) {
+		return err
+	}
+	du.Status.Output = commandOutput(res)
+	return err
+}
+
+// cleanupVerb describes what a deletion policy does to the account, for events.
+func cleanupVerb(policy djangov1alpha1.UserDeletionPolicy) string {
+	if policy == djangov1alpha1.UserDeletionDelete {
+		return "Deleted"
+	}
+	return "Deactivated"
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *DjangoUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	// initialize REST config & clientset
-	RestCFG := mgr.GetConfig()
-	cs, err := kubernetes.NewForConfig(RestCFG)
-	if err != nil {
+	// wire in the real PodRunners
+	var err error
+	if r.Pods, r.Jobs, err = podRunners(mgr, r.Client, r.DjangoPodlabel); err != nil {
 		return err
 	}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("djangouser-controller")
+	}
 
-	// wire in the real PodRunner
-	r.Pods = DjangoPodRunner{
-		Client:    r.Client,
-		RESTCfg:   RestCFG,
-		Clientset: cs,
-		Label:     r.DjangoPodlabel,
+	// index DjangoUsers by password Secret so Secret changes can be mapped back to them
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&djangov1alpha1.DjangoUser{},
+		passwordSecretField,
+		func(o client.Object) []string {
+			return []string{o.(*djangov1alpha1.DjangoUser).Spec.PasswordSecretRef.Name}
+		},
+	); err != nil {
+		return err
 	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&djangov1alpha1.DjangoUser{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.usersForSecret)).
 		Named("djangouser").
 		Complete(r)
 }
+
+// usersForSecret maps a Secret to the DjangoUsers whose password it holds.
+func (r *DjangoUserReconciler) usersForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var users djangov1alpha1.DjangoUserList
+	if err := r.List(ctx, &users,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{passwordSecretField: secret.GetName()},
+	); err != nil {
+		logf.FromContext(ctx).Error(err, "listing DjangoUsers for secret", "secret", secret.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(users.Items))
+	for _, du := range users.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: du.Namespace, Name: du.Name},
+		})
+	}
+	return requests
+}
@@ -18,13 +18,18 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
@@ -45,8 +50,30 @@ func (t testPodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.Po
 	}, nil
 }
 
-func (t testPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, command []string) error {
-	return nil
+func (t testPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, req ExecRequest) (ExecResult, error) {
+	return ExecResult{Stdout: "OK"}, nil
+}
+
+// failingPodRunner is a fake PodRunner whose commands always exit with an error.
+type failingPodRunner struct {
+	testPodRunner
+}
+
+func (t failingPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, req ExecRequest) (ExecResult, error) {
+	return ExecResult{Stderr: "django.db.utils.OperationalError", ExitCode: 1}, fmt.Errorf("command terminated with exit code 1")
+}
+
+// recordingPodRunner is a fake PodRunner that records every ExecRequest it receives
+// and prints stdout.
+type recordingPodRunner struct {
+	testPodRunner
+	requests []ExecRequest
+	stdout   string
+}
+
+func (t *recordingPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, req ExecRequest) (ExecResult, error) {
+	t.requests = append(t.requests, req)
+	return ExecResult{Stdout: t.stdout}, nil
 }
 
 var _ = Describe("DjangoUser Controller", func() {
@@ -129,6 +156,357 @@ var _ = Describe("DjangoUser Controller", func() {
 
 			// Ensure Status.Created is non-zero
 			Expect(updated.Status.Created.IsZero()).To(BeFalse(), "expected Status.Created to be set")
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(updated.Status.Attempts).To(BeEquivalentTo(1))
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, djangov1alpha1.ConditionReady)).To(BeTrue())
+		})
+	})
+
+	Context("When credentials contain special characters", func() {
+		const resourceName = "test-special-chars"
+		const username = `o'brien "\\admin"`
+		const password = `it's a "p\\w'd"; import os -- ñandú 🔑`
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		pwSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pw-" + resourceName,
+				Namespace: typeNamespacedName.Namespace,
+			},
+			StringData: map[string]string{
+				"password": password,
+			},
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, pwSecret.DeepCopy())).To(Succeed())
+			resource := &djangov1alpha1.DjangoUser{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: djangov1alpha1.DjangoUserSpec{
+					Username: username,
+					Email:    "ñandú@example.com",
+					PasswordSecretRef: djangov1alpha1.SecretKeySelector{
+						Name: pwSecret.Name,
+						Key:  "password",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, pwSecret.DeepCopy())).To(Succeed())
+		})
+
+		It("should pass them verbatim on stdin and never in the command", func() {
+			runner := &recordingPodRunner{}
+			tr := &DjangoUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+			_, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(HaveLen(1))
+
+			var params userParams
+			Expect(json.Unmarshal(runner.requests[0].Stdin, &params)).To(Succeed())
+			Expect(params.Username).To(Equal(username))
+			Expect(params.Password).To(Equal(password))
+			Expect(params.Email).To(Equal("ñandú@example.com"))
+
+			for _, arg := range runner.requests[0].Command {
+				Expect(arg).NotTo(ContainSubstring(password))
+				Expect(arg).NotTo(ContainSubstring(username))
+			}
+		})
+	})
+	Context("When building the user command", func() {
+		newUser := func(superuser bool, staff, active *bool) *djangov1alpha1.DjangoUser {
+			return &djangov1alpha1.DjangoUser{
+				Spec: djangov1alpha1.DjangoUserSpec{
+					Username:  "alice",
+					Email:     "alice@example.com",
+					Superuser: superuser,
+					Staff:     staff,
+					Active:    active,
+				},
+			}
+		}
+		params := func(stdin []byte) map[string]any {
+			var m map[string]any
+			Expect(json.Unmarshal(stdin, &m)).To(Succeed())
+			return m
+		}
+
+		It("should pass flags as booleans, not strings", func() {
+			cmd, stdin, err := createUserCommand(newUser(false, nil, nil), "pw")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cmd).To(Equal([]string{"python", "manage.py", "shell", "-c", createUserScript}))
+			Expect(params(stdin)).To(HaveKeyWithValue("superuser", false))
+			Expect(params(stdin)).To(HaveKeyWithValue("staff", true))
+			Expect(params(stdin)).To(HaveKeyWithValue("active", true))
+			Expect(createUserScript).To(ContainSubstring(`u.is_superuser = params["superuser"]`))
+			Expect(createUserScript).To(ContainSubstring(`u.is_staff = params["staff"]`))
+			Expect(createUserScript).To(ContainSubstring(`u.is_active = params["active"]`))
+		})
+
+		It("should keep the password hash when the password is unchanged", func() {
+			shellCmd, _, err := createUserCommand(newUser(false, nil, nil), "pw")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(shellCmd[len(shellCmd)-1]).To(ContainSubstring(
+				"if not u.check_password(params[\"password\"]):\n    u.set_password"))
+		})
+
+		It("should fingerprint the password with the operator's key", func() {
+			fingerprint := passwordFingerprint([]byte("key"), []byte("pw"))
+			Expect(fingerprint).To(Equal(passwordFingerprint([]byte("key"), []byte("pw"))))
+			Expect(fingerprint).NotTo(Equal(passwordFingerprint([]byte("other"), []byte("pw"))))
+			Expect(fingerprint).NotTo(Equal(passwordFingerprint([]byte("key"), []byte("pw2"))))
+		})
+
+		It("should set superuser, staff and active independently", func() {
+			_, stdin, err := createUserCommand(newUser(true, ptr.To(false), ptr.To(false)), "pw")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params(stdin)).To(HaveKeyWithValue("superuser", true))
+			Expect(params(stdin)).To(HaveKeyWithValue("staff", false))
+			Expect(params(stdin)).To(HaveKeyWithValue("active", false))
+		})
+	})
+	Context("When the password Secret is rotated", func() {
+		const resourceName = "test-rotation"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		secretName := types.NamespacedName{
+			Name:      "pw-" + resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: secretName.Name, Namespace: secretName.Namespace},
+				StringData: map[string]string{"password": "first"},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoUser{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: djangov1alpha1.DjangoUserSpec{
+					Username: resourceName,
+					PasswordSecretRef: djangov1alpha1.SecretKeySelector{
+						Name: secretName.Name,
+						Key:  "password",
+					},
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretName, secret)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		It("should apply the new password", func() {
+			runner := &recordingPodRunner{}
+			tr := &DjangoUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+			reconcileUser := func() {
+				_, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("creating the user")
+			reconcileUser()
+			Expect(runner.requests).To(HaveLen(1))
+			updated := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			firstFingerprint := updated.Status.PasswordFingerprint
+			Expect(firstFingerprint).NotTo(BeEmpty())
+
+			By("doing nothing while the Secret is unchanged")
+			reconcileUser()
+			Expect(runner.requests).To(HaveLen(1))
+
+			By("doing nothing when the Secret changes but not the password")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretName, secret)).To(Succeed())
+			secret.Labels = map[string]string{"rotated-by": "ops"}
+			secret.Data["username"] = []byte(resourceName)
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			reconcileUser()
+			Expect(runner.requests).To(HaveLen(1))
+
+			By("re-applying the user once the password changes")
+			secret.Data["password"] = []byte("second")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+			reconcileUser()
+			Expect(runner.requests).To(HaveLen(2))
+			var params userParams
+			Expect(json.Unmarshal(runner.requests[1].Stdin, &params)).To(Succeed())
+			Expect(params.Password).To(Equal("second"))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			Expect(updated.Status.PasswordFingerprint).NotTo(Equal(firstFingerprint))
+		})
+	})
+	Context("When a DjangoUser with a Delete policy is deleted", func() {
+		const resourceName = "test-deletion"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		pwSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pw-" + resourceName,
+				Namespace: typeNamespacedName.Namespace,
+			},
+			StringData: map[string]string{"password": "S3cr3t"},
+		}
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, pwSecret.DeepCopy())).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoUser{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: djangov1alpha1.DjangoUserSpec{
+					Username: resourceName,
+					PasswordSecretRef: djangov1alpha1.SecretKeySelector{
+						Name: pwSecret.Name,
+						Key:  "password",
+					},
+					DeletionPolicy: djangov1alpha1.UserDeletionDelete,
+				},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, pwSecret.DeepCopy())).To(Succeed())
+		})
+
+		It("should delete the Django account before releasing the CR", func() {
+			runner := &recordingPodRunner{}
+			tr := &DjangoUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   runner,
+			}
+
+			By("adding the finalizer while creating the user")
+			_, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(userFinalizer))
+
+			By("running the cleanup command once the CR is deleted")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(HaveLen(2))
+			Expect(runner.requests[1].Command).To(ContainElement(cleanupUserScript))
+			var params userCleanupParams
+			Expect(json.Unmarshal(runner.requests[1].Stdin, &params)).To(Succeed())
+			Expect(params).To(Equal(userCleanupParams{Username: resourceName, Delete: true}))
+
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should skip the cleanup once the Django app is gone or on request", func() {
+			recorder := record.NewFakeRecorder(10)
+			tr := &DjangoUserReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				Pods:           &recordingPodRunner{},
+				Recorder:       recorder,
+				DjangoPodlabel: PodLabel{"app": "user-cleanup-test"},
+			}
+			_, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("waiting for a pod while the DjangoApp is there")
+			app := &djangov1alpha1.DjangoApp{ObjectMeta: metav1.ObjectMeta{Name: "user-cleanup-app", Namespace: "default"}}
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+			tr.Pods = noPodRunner{}
+			result, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(userFinalizer))
+
+			By("dropping the finalizer once the DjangoApp is gone")
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+			_, err = tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(HavePrefix("Warning " + EventCleanupSkipped)))
+		})
+
+		It("should skip the cleanup when annotated", func() {
+			tr := &DjangoUserReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   &recordingPodRunner{},
+			}
+			_, err := tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &djangov1alpha1.DjangoUser{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Annotations = map[string]string{djangov1alpha1.UserSkipCleanupAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			runner := &recordingPodRunner{}
+			tr.Pods = runner
+			_, err = tr.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(runner.requests).To(BeEmpty())
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("When retrying the cleanup of a user", func() {
+		It("should give up after a bounded number of attempts", func() {
+			Expect(cleanupRetryPolicy(nil).MaxAttempts).To(BeEquivalentTo(defaultCleanupAttempts))
+			policy := &djangov1alpha1.RetryPolicy{MaxAttempts: 3}
+			Expect(cleanupRetryPolicy(policy).MaxAttempts).To(BeEquivalentTo(3))
+
+			st := &djangov1alpha1.CommandStatus{Attempts: defaultCleanupAttempts}
+			handleFailure(st, 1, cleanupRetryPolicy(nil), ReasonCleanupFailed, fmt.Errorf("boom"))
+			Expect(st.Phase).To(Equal(djangov1alpha1.PhaseFailed))
 		})
 	})
 })
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reasons of the events emitted along the lifecycle of a command.
+const (
+	EventPodNotFound   = "PodNotFound"
+	EventExecStarted   = "ExecStarted"
+	EventExecSucceeded = "ExecSucceeded"
+	EventExecFailed    = "ExecFailed"
+	EventPruned        = "Pruned"
+	// EventCleanupSkipped is emitted when a DjangoUser is deleted without its cleanup
+	EventCleanupSkipped = "CleanupSkipped"
+	// EventPlanRecorded and EventPreApplyRecorded are emitted on a DjangoMigrate when the
+	// intermediate steps before its migrate succeed
+	EventPlanRecorded     = "PlanRecorded"
+	EventPreApplyRecorded = "PreApplyRecorded"
+	// EventAutoMigrate is emitted on a DjangoApp when a run is created for a new image
+	EventAutoMigrate = "AutoMigrate"
+	// EventPipelineStep is emitted on a DjangoPipeline as its steps start and finish
+	EventPipelineStep = "PipelineStep"
+)
+
+// helmInstanceLabel is set by the chart on the pods of a release; the release of a
+// DjangoApp is named after it.
+const helmInstanceLabel = "app.kubernetes.io/instance"
+
+// commandEvents emits events both on a command CR and on the DjangoApp running the
+// pods the command targets, so `kubectl describe` on either shows what happened.
+type commandEvents struct {
+	client.Reader
+	Recorder record.EventRecorder
+}
+
+// emit records an event on obj and, prefixed with the kind and name of obj, on the
+// DjangoApp of pod. Without a recorder it does nothing.
+func (e commandEvents) emit(ctx context.Context, obj client.Object, kind string, pod *corev1.Pod,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	if e.Recorder == nil {
+		return
+	}
+	msg := fmt.Sprintf(messageFmt, args...)
+	e.Recorder.Event(obj, eventtype, reason, msg)
+	if app := e.appFor(ctx, obj.GetNamespace(), pod); app != nil {
+		e.Recorder.Eventf(app, eventtype, reason, "%s %s: %s", kind, obj.GetName(), msg)
+	}
+}
+
+// appFor returns the DjangoApp whose release runs pod or, when that is unknown, the only
+// DjangoApp of the namespace. It returns nil if there is none.
+func (e commandEvents) appFor(ctx context.Context, namespace string, pod *corev1.Pod) *djangov1alpha1.DjangoApp {
+	logger := logf.FromContext(ctx)
+	if pod != nil && pod.Labels[helmInstanceLabel] != "" {
+		var app djangov1alpha1.DjangoApp
+		err := e.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pod.Labels[helmInstanceLabel]}, &app)
+		if err == nil {
+			return &app
+		}
+		if client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "looking up DjangoApp for events", "pod", pod.Name)
+		}
+	}
+	var apps djangov1alpha1.DjangoAppList
+	if err := e.List(ctx, &apps, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "listing DjangoApps for events")
+		return nil
+	}
+	if len(apps.Items) != 1 {
+		return nil
+	}
+	return &apps.Items[0]
+}
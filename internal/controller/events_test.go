@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// appPodRunner is a fake PodRunner whose pod belongs to the release of a DjangoApp.
+type appPodRunner struct {
+	testPodRunner
+	app string
+}
+
+func (t appPodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.Pod, error) {
+	pod, _ := t.testPodRunner.FindDjangoPod(ctx, ns)
+	pod.Labels = map[string]string{helmInstanceLabel: t.app}
+	return pod, nil
+}
+
+// noPodRunner is a fake PodRunner that never finds a ready pod.
+type noPodRunner struct {
+	testPodRunner
+}
+
+func (t noPodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.Pod, error) {
+	return nil, nil
+}
+
+var _ = Describe("Command events", func() {
+	const resourceName = "events-static"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+
+	BeforeEach(func() {
+		Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoStatic{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+		})).To(Succeed())
+		Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "events-app", Namespace: "default"},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoStatic{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+		})).To(Succeed())
+		Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "events-app", Namespace: "default"},
+		})).To(Succeed())
+	})
+
+	It("should emit the lifecycle on the CR and on the DjangoApp", func() {
+		recorder := record.NewFakeRecorder(10)
+		controllerReconciler := &DjangoStaticReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Pods:     appPodRunner{app: "events-app"},
+			Recorder: recorder,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal " + EventExecStarted + " Attempt 1")))
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix("Normal "+EventExecStarted),
+			ContainSubstring("DjangoStatic "+resourceName+":"))))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal " + EventExecSucceeded)))
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix("Normal "+EventExecSucceeded),
+			ContainSubstring("DjangoStatic "+resourceName+":"))))
+	})
+
+	It("should warn when no pod is found", func() {
+		recorder := record.NewFakeRecorder(10)
+		controllerReconciler := &DjangoStaticReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Pods:     noPodRunner{},
+			Recorder: recorder,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning " + EventPodNotFound)))
+		// The only DjangoApp of the namespace is told too
+		Expect(recorder.Events).To(Receive(And(
+			HavePrefix("Warning "+EventPodNotFound),
+			ContainSubstring("DjangoStatic "+resourceName+":"))))
+	})
+})
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	charts "github.com/jvdiago/django-helm-template"
@@ -19,28 +18,89 @@ import (
 	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
 )
 
-// specTranslator reads spec.Values into chartutil.Values
-func specTranslator(c client.Client) values.Translator {
+// specTranslator maps the spec of a DjangoApp onto the chart values. A DjangoApp migrating PreUpgrade
+// is kept on its previously migrated image until the migrations of the new one succeed.
+func specTranslator(c client.Client, defaults chartutil.Values) values.Translator {
 	return values.TranslatorFunc(func(ctx context.Context, u *unstructured.Unstructured) (chartutil.Values, error) {
 		// convert Unstructured → typed CR
 		app := &djangov1alpha1.DjangoApp{}
 		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, app); err != nil {
 			return nil, err
 		}
-		if app.Spec.Values == nil {
-			return chartutil.Values{}, nil
+		held, err := heldImage(ctx, c, app, defaults)
+		if err != nil {
+			return nil, err
 		}
-		// return exactly what the user put under `.spec.values`
-		var m map[string]interface{}
-		if err := json.Unmarshal(app.Spec.Values.Raw, &m); err != nil {
+		m, err := AppValues(app, defaults)
+		if err != nil {
 			return nil, err
 		}
-
-		// 4) Wrap and return
-		return chartutil.Values(m), nil
+		if held != "" {
+			logf.FromContext(ctx).Info("holding the release until its migrations succeed", "image", held)
+			withImage(m, held)
+		}
+		return m, nil
 	})
 }
 
+// appStatusFields are the fields of the status of a DjangoApp written by the operator's
+// own controllers rather than the Helm reconciler.
+var appStatusFields = []string{"created", "release", "django", "celery", "autoMigrate"}
+
+// helmStatusClient is the client of the Helm reconciler. The Helm reconciler replaces the
+// whole status of a DjangoApp with its conditions; this client puts the fields it does
+// not own back before the update is sent, so they don't blink out until rewritten.
+type helmStatusClient struct {
+	client.Client
+}
+
+func (c helmStatusClient) Status() client.SubResourceWriter {
+	return helmStatusWriter{SubResourceWriter: c.Client.Status(), reader: c.Client}
+}
+
+type helmStatusWriter struct {
+	client.SubResourceWriter
+	reader client.Reader
+}
+
+func (w helmStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		if err := keepAppStatus(ctx, w.reader, u); err != nil {
+			return err
+		}
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// keepAppStatus copies the appStatusFields of the stored DjangoApp u into the status of u
+// where u has none. The update of u carries its resourceVersion, so it fails with a
+// conflict if the copied fields were stale.
+func keepAppStatus(ctx context.Context, c client.Reader, u *unstructured.Unstructured) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), current); err != nil {
+		// The update fails the same way
+		return client.IgnoreNotFound(err)
+	}
+	stored, _, err := unstructured.NestedMap(current.Object, "status")
+	if err != nil || stored == nil {
+		return err
+	}
+	status, _, err := unstructured.NestedMap(u.Object, "status")
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		status = map[string]interface{}{}
+	}
+	for _, field := range appStatusFields {
+		if _, set := status[field]; !set && stored[field] != nil {
+			status[field] = stored[field]
+		}
+	}
+	return unstructured.SetNestedMap(u.Object, status, "status")
+}
+
 // +kubebuilder:rbac:groups=apps.django.djangooperator,resources=djangoapps,verbs=get;list;watch;create;update;patch;delete
 // SetupHelmController wires the generic Helm-based reconciler into the manager.
 func SetupHelmController(mgr ctrl.Manager) error {
@@ -54,6 +114,7 @@ func SetupHelmController(mgr ctrl.Manager) error {
 	// 	panic(err)
 	// }
 	r, err := reconciler.New(
+		reconciler.WithClient(helmStatusClient{Client: mgr.GetClient()}),
 		reconciler.WithChart(*chartObj),
 		reconciler.WithGroupVersionKind(schema.GroupVersionKind{
 			Group:   "django.djangooperator",
@@ -63,7 +124,7 @@ func SetupHelmController(mgr ctrl.Manager) error {
 		reconciler.SkipDependentWatches(true),
 		reconciler.WithMaxConcurrentReconciles(1),
 		reconciler.SkipPrimaryGVKSchemeRegistration(true),
-		reconciler.WithValueTranslator(specTranslator(mgr.GetClient())),
+		reconciler.WithValueTranslator(specTranslator(mgr.GetClient(), chartObj.Values)),
 		reconciler.WithLog(logf.Log.WithName("helm").WithName("DjangoApp")),
 	)
 	if err != nil {
@@ -0,0 +1,363 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+const (
+	// jobContainerName is the name of the container running the command in a Job.
+	jobContainerName = "command"
+	// defaultJobTimeout bounds how long a Job may run, as its activeDeadlineSeconds.
+	defaultJobTimeout = 30 * time.Minute
+	// jobRequeueInterval is how often the CR of a running Job is reconciled to check on it.
+	jobRequeueInterval = 5 * time.Second
+	// jobTTLAfterFinished lets Kubernetes clean up Jobs the runner failed to delete.
+	jobTTLAfterFinished = 10 * 60
+	// stdinEnvVar carries ExecRequest.Stdin into the Job, as Jobs cannot attach stdin.
+	stdinEnvVar = "DJANGO_OPERATOR_STDIN"
+	// stdinSecretKey is the key of the Secret holding ExecRequest.Stdin.
+	stdinSecretKey = "stdin"
+	// jobOwnerLabel holds the UID of the CR a Job runs a command for.
+	jobOwnerLabel = "django.djangooperator/owner-uid"
+	// jobRequestLabel holds a hash of the request a Job runs, telling apart the Jobs of
+	// successive requests of one CR.
+	jobRequestLabel = "django.djangooperator/request"
+)
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// JobPodRunner runs commands in a dedicated batch/v1 Job built from the Django pod's
+// image, env and volumes, instead of exec'ing into the serving pod.
+type JobPodRunner struct {
+	DjangoPodRunner
+	// Timeout bounds how long the Job may run. Defaults to defaultJobTimeout.
+	Timeout time.Duration
+}
+
+// jobRunningError is returned by JobPodRunner.ExecInPod while the Job of a request is
+// still running. The caller requeues and calls ExecInPod again with the same request,
+// which picks the Job up, even from another operator instance.
+type jobRunningError struct {
+	job string
+}
+
+func (e *jobRunningError) Error() string { return "job " + e.job + " is still running" }
+
+// jobRunning reports whether err tells that the Job of a request is still running.
+func jobRunning(err error) bool {
+	var running *jobRunningError
+	return errors.As(err, &running)
+}
+
+// ExecInPod launches a Job using pod as a template and returns a jobRunningError until
+// it finishes. Once it has, it returns the tail of its logs and the exit code of the
+// command, and deletes the Job. The Job is labelled with req.Owner, so that calls for
+// the same request adopt it instead of launching another one.
+func (r JobPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, req ExecRequest) (ExecResult, error) {
+	if req.Owner == "" {
+		return ExecResult{ExitCode: -1}, fmt.Errorf("running a command in a job needs the UID of its owner")
+	}
+	hash, err := requestHash(req)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+	jobs := r.Clientset.BatchV1().Jobs(pod.Namespace)
+	job, err := r.adoptJob(ctx, pod.Namespace, req.Owner, hash)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+	if job == nil {
+		if job, err = r.createJob(ctx, pod, req, hash); err != nil {
+			return ExecResult{ExitCode: -1}, err
+		}
+	}
+
+	finished := jobFinished(job)
+	if finished == nil {
+		return ExecResult{ExitCode: -1}, &jobRunningError{job: job.Name}
+	}
+	res := r.jobResult(ctx, job)
+	// Background propagation also removes the pod and the stdin Secret owned by the Job
+	if err := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	}); err != nil && !apierrors.IsNotFound(err) {
+		return res, fmt.Errorf("deleting job %s: %w", job.Name, err)
+	}
+	if finished.Type == batchv1.JobFailed {
+		return res, fmt.Errorf("job %s failed: %s", job.Name, finished.Message)
+	}
+	return res, nil
+}
+
+// adoptJob returns the Job launched for the request with hash by owner, or nil if
+// there is none. Jobs left over from earlier requests of owner are deleted once they
+// finish; until then a jobRunningError is returned, so the requests do not overlap.
+func (r JobPodRunner) adoptJob(ctx context.Context, namespace string, owner types.UID, hash string) (*batchv1.Job, error) {
+	jobs := r.Clientset.BatchV1().Jobs(namespace)
+	list, err := jobs.List(ctx, metav1.ListOptions{LabelSelector: jobOwnerLabel + "=" + string(owner)})
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+	var adopted *batchv1.Job
+	for i := range list.Items {
+		job := &list.Items[i]
+		switch {
+		case !job.DeletionTimestamp.IsZero():
+			continue
+		case job.Labels[jobRequestLabel] == hash:
+			adopted = job
+		case jobFinished(job) == nil:
+			return nil, &jobRunningError{job: job.Name}
+		default:
+			if err := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{
+				PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+			}); err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("deleting job %s: %w", job.Name, err)
+			}
+		}
+	}
+	return adopted, nil
+}
+
+// createJob launches the Job running req with pod as a template. Stdin is handed over
+// through a Secret owned by the Job rather than the Job spec.
+func (r JobPodRunner) createJob(ctx context.Context, pod *corev1.Pod, req ExecRequest, hash string) (*batchv1.Job, error) {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "django-operator",
+		jobOwnerLabel:                  string(req.Owner),
+		jobRequestLabel:                hash,
+	}
+	secrets := r.Clientset.CoreV1().Secrets(pod.Namespace)
+	var secret *corev1.Secret
+	if req.Stdin != nil {
+		var err error
+		secret, err = secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "django-command-stdin-",
+				Namespace:    pod.Namespace,
+				Labels:       labels,
+			},
+			Data: map[string][]byte{stdinSecretKey: req.Stdin},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("creating stdin secret: %w", err)
+		}
+	}
+	stdinSecret := ""
+	if secret != nil {
+		stdinSecret = secret.Name
+	}
+
+	template, err := jobForCommand(pod, req, stdinSecret)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(template.Labels, labels)
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = defaultJobTimeout
+	}
+	template.Spec.ActiveDeadlineSeconds = ptr.To(int64(timeout / time.Second))
+	job, err := r.Clientset.BatchV1().Jobs(pod.Namespace).Create(ctx, template, metav1.CreateOptions{})
+	if err != nil {
+		if secret != nil {
+			_ = secrets.Delete(ctx, secret.Name, metav1.DeleteOptions{})
+		}
+		return nil, fmt.Errorf("creating job: %w", err)
+	}
+	if secret != nil {
+		secret.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job")),
+		}
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("handing stdin secret over to job %s: %w", job.Name, err)
+		}
+	}
+	return job, nil
+}
+
+// jobFinished returns the Complete or Failed condition of job, or nil while it runs.
+func jobFinished(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		c := &job.Status.Conditions[i]
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return c
+		}
+	}
+	return nil
+}
+
+// requestHash returns a short hash identifying what req runs. Stdin is left out, as it
+// may carry secrets.
+func requestHash(req ExecRequest) (string, error) {
+	data, err := json.Marshal(struct {
+		Container string
+		Command   []string
+		Env       []corev1.EnvVar
+		Image     string
+	}{req.Container, req.Command, req.Env, req.Image})
+	if err != nil {
+		return "", fmt.Errorf("hashing request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// jobResult collects the logs and exit code of the pod created by job.
+func (r JobPodRunner) jobResult(ctx context.Context, job *batchv1.Job) ExecResult {
+	res := ExecResult{ExitCode: -1}
+	if job.Status.StartTime != nil {
+		res.Started = job.Status.StartTime.Time
+	}
+	pods, err := r.Clientset.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: batchv1.JobNameLabel + "=" + job.Name,
+	})
+	if err != nil || len(pods.Items) == 0 {
+		return res
+	}
+	// With backoffLimit 0 there is a single pod
+	jobPod := pods.Items[0]
+	for _, cs := range jobPod.Status.ContainerStatuses {
+		if cs.Name == jobContainerName && cs.State.Terminated != nil {
+			res.ExitCode = int(cs.State.Terminated.ExitCode)
+		}
+	}
+	res.ImageID = imageID(&jobPod, jobContainerName)
+	stream, err := r.Clientset.CoreV1().Pods(job.Namespace).
+		GetLogs(jobPod.Name, &corev1.PodLogOptions{Container: jobContainerName}).
+		Stream(ctx)
+	if err != nil {
+		return res
+	}
+	defer stream.Close() //nolint:errcheck
+	logs := &tailBuffer{limit: maxExecOutputBytes}
+	_, _ = io.Copy(logs, stream)
+	// Job logs interleave stdout and stderr
+	res.Stdout = logs.String()
+	return res
+}
+
+// jobForCommand builds a Job running the requested command with the image, env and
+// volumes of the requested container of pod, or the requested image if any. If
+// stdinSecret is set, the content of that Secret is piped into the command's standard input.
+func jobForCommand(pod *corev1.Pod, req ExecRequest, stdinSecret string) (*batchv1.Job, error) {
+	name, err := containerFor(pod, req.Container, "")
+	if err != nil {
+		return nil, err
+	}
+	var src corev1.Container
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			src = c
+		}
+	}
+
+	image := src.Image
+	if req.Image != "" {
+		image = req.Image
+	}
+	command := req.Command
+	// Later entries win, so the requested env overrides the container's
+	env := append(append([]corev1.EnvVar{}, src.Env...), req.Env...)
+	if stdinSecret != "" {
+		command = append([]string{"sh", "-c", `printf '%s' "$` + stdinEnvVar + `" | exec "$@"`, "sh"}, command...)
+		env = append(env, corev1.EnvVar{
+			Name: stdinEnvVar,
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: stdinSecret},
+				Key:                  stdinSecretKey,
+			}},
+		})
+	}
+
+	var volumes []corev1.Volume
+	for _, v := range pod.Spec.Volumes {
+		// The service account token volume is injected again by the API server
+		if strings.HasPrefix(v.Name, "kube-api-access-") {
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	var mounts []corev1.VolumeMount
+	for _, m := range src.VolumeMounts {
+		if strings.HasPrefix(m.Name, "kube-api-access-") {
+			continue
+		}
+		mounts = append(mounts, m)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "django-command-",
+			Namespace:    pod.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "django-operator",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			// Retries are driven by the operator, not by the Job controller
+			BackoffLimit:            ptr.To[int32](0),
+			TTLSecondsAfterFinished: ptr.To[int32](jobTTLAfterFinished),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "django-operator",
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: pod.Spec.ServiceAccountName,
+					ImagePullSecrets:   pod.Spec.ImagePullSecrets,
+					SecurityContext:    pod.Spec.SecurityContext,
+					NodeSelector:       pod.Spec.NodeSelector,
+					Tolerations:        pod.Spec.Tolerations,
+					Volumes:            volumes,
+					Containers: []corev1.Container{{
+						Name:            jobContainerName,
+						Image:           image,
+						ImagePullPolicy: src.ImagePullPolicy,
+						Command:         command,
+						WorkingDir:      src.WorkingDir,
+						Env:             env,
+						EnvFrom:         src.EnvFrom,
+						VolumeMounts:    mounts,
+						Resources:       src.Resources,
+						SecurityContext: src.SecurityContext,
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// runnerFor returns the PodRunner a CR should use: its own spec.runner wins over the
+// operator-wide default. Exec is used when no Job runner is wired in.
+func runnerFor(exec, job PodRunner, def, override djangov1alpha1.RunnerType) PodRunner {
+	runner := def
+	if override != "" {
+		runner = override
+	}
+	if runner == djangov1alpha1.RunnerJob && job != nil {
+		return job
+	}
+	return exec
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("JobPodRunner", func() {
+	It("should build a Job from the Django pod template", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "django-abc", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: "django",
+				Volumes: []corev1.Volume{
+					{Name: "media"},
+					{Name: "kube-api-access-x1y2z"},
+				},
+				Containers: []corev1.Container{{
+					Name:  "django",
+					Image: "registry/django:v2",
+					Env:   []corev1.EnvVar{{Name: "DATABASE_URL", Value: "postgres://db"}},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "media", MountPath: "/media"},
+						{Name: "kube-api-access-x1y2z", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"},
+					},
+				}},
+			},
+		}
+		job, err := jobForCommand(pod, ExecRequest{Command: []string{"python", "manage.py", "migrate"}}, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(job.Namespace).To(Equal("default"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+		spec := job.Spec.Template.Spec
+		Expect(spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(spec.ServiceAccountName).To(Equal("django"))
+		Expect(spec.Volumes).To(ConsistOf(corev1.Volume{Name: "media"}))
+		Expect(spec.Containers).To(HaveLen(1))
+		c := spec.Containers[0]
+		Expect(c.Image).To(Equal("registry/django:v2"))
+		Expect(c.Command).To(Equal([]string{"python", "manage.py", "migrate"}))
+		Expect(c.Env).To(Equal(pod.Spec.Containers[0].Env))
+		Expect(c.VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: "media", MountPath: "/media"}))
+	})
+
+	It("should pipe stdin from a Secret instead of the Job spec", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "django-abc", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "django", Image: "django"}}},
+		}
+		job, err := jobForCommand(pod, ExecRequest{
+			Command: []string{"python", "manage.py", "shell", "-c", "print(1)"},
+			Stdin:   []byte(`{"password": "s3cr3t"}`),
+		}, "stdin-secret")
+		Expect(err).NotTo(HaveOccurred())
+
+		c := job.Spec.Template.Spec.Containers[0]
+		Expect(c.Command[:2]).To(Equal([]string{"sh", "-c"}))
+		Expect(c.Command[4:]).To(Equal([]string{"python", "manage.py", "shell", "-c", "print(1)"}))
+		Expect(c.Env).To(ContainElement(HaveField("ValueFrom.SecretKeyRef.Name", "stdin-secret")))
+		Expect(job.String()).NotTo(ContainSubstring("s3cr3t"))
+		Expect(pod.Spec.Containers[0].Env).To(BeEmpty())
+	})
+
+	It("should let the requested env override the container's", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "django-abc", Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "django", Image: "django", Env: []corev1.EnvVar{{Name: "DEBUG", Value: "0"}},
+			}}},
+		}
+		job, err := jobForCommand(pod, ExecRequest{
+			Command: []string{"python", "manage.py", "clearsessions"},
+			Env:     []corev1.EnvVar{{Name: "DEBUG", Value: "1"}},
+		}, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		env := job.Spec.Template.Spec.Containers[0].Env
+		Expect(env[len(env)-1]).To(Equal(corev1.EnvVar{Name: "DEBUG", Value: "1"}))
+	})
+
+	It("should run the requested image instead of the pod's", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "django-abc", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "django", Image: "registry/django:v1"}}},
+		}
+		job, err := jobForCommand(pod, ExecRequest{
+			Command: []string{"python", "manage.py", "migrate"},
+			Image:   "registry/django:v2",
+		}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("registry/django:v2"))
+	})
+
+	It("should let the CR override the operator-wide runner", func() {
+		exec, job := testPodRunner{}, JobPodRunner{}
+		Expect(runnerFor(exec, job, djangov1alpha1.RunnerExec, "")).To(Equal(exec))
+		Expect(runnerFor(exec, job, djangov1alpha1.RunnerJob, "")).To(Equal(job))
+		Expect(runnerFor(exec, job, djangov1alpha1.RunnerExec, djangov1alpha1.RunnerJob)).To(Equal(job))
+		Expect(runnerFor(exec, job, djangov1alpha1.RunnerJob, djangov1alpha1.RunnerExec)).To(Equal(exec))
+		Expect(runnerFor(exec, nil, djangov1alpha1.RunnerJob, "")).To(Equal(exec))
+	})
+
+	Context("When a Job outlives the call that launched it", func() {
+		var (
+			ctx    context.Context
+			cs     *fake.Clientset
+			runner JobPodRunner
+			pod    *corev1.Pod
+		)
+		BeforeEach(func() {
+			ctx = context.Background()
+			cs = fake.NewClientset()
+			runner = JobPodRunner{DjangoPodRunner: DjangoPodRunner{Clientset: cs}}
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "django-abc", Namespace: "default"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "django", Image: "django"}}},
+			}
+		})
+
+		jobs := func() []batchv1.Job {
+			list, err := cs.BatchV1().Jobs("default").List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			return list.Items
+		}
+		finish := func(job batchv1.Job, condition batchv1.JobConditionType) {
+			job.Status.StartTime = &metav1.Time{Time: job.CreationTimestamp.Time}
+			job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+			_, err := cs.BatchV1().Jobs("default").UpdateStatus(ctx, &job, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("should adopt the Job of an earlier call instead of launching another", func() {
+			req := ExecRequest{Command: []string{"python", "manage.py", "migrate"}, Owner: "uid-1"}
+			_, err := runner.ExecInPod(ctx, pod, req)
+			Expect(jobRunning(err)).To(BeTrue())
+			Expect(jobs()).To(HaveLen(1))
+			job := jobs()[0]
+			Expect(job.Labels).To(HaveKeyWithValue(jobOwnerLabel, "uid-1"))
+			Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(defaultJobTimeout.Seconds())))
+
+			// E.g. after an operator restart
+			_, err = runner.ExecInPod(ctx, pod, req)
+			Expect(jobRunning(err)).To(BeTrue())
+			Expect(jobs()).To(ConsistOf(HaveField("Name", job.Name)))
+
+			finish(job, batchv1.JobComplete)
+			_, err = runner.ExecInPod(ctx, pod, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs()).To(BeEmpty())
+		})
+
+		It("should let the Job of an earlier request finish before launching another", func() {
+			plan := ExecRequest{Command: []string{"python", "manage.py", "migrate", "--plan"}, Owner: "uid-1"}
+			apply := ExecRequest{Command: []string{"python", "manage.py", "migrate"}, Owner: "uid-1"}
+			_, err := runner.ExecInPod(ctx, pod, plan)
+			Expect(jobRunning(err)).To(BeTrue())
+			planJob := jobs()[0]
+
+			_, err = runner.ExecInPod(ctx, pod, apply)
+			Expect(err).To(MatchError(ContainSubstring(planJob.Name)))
+			Expect(jobs()).To(HaveLen(1))
+
+			finish(planJob, batchv1.JobFailed)
+			_, err = runner.ExecInPod(ctx, pod, apply)
+			Expect(jobRunning(err)).To(BeTrue())
+			Expect(jobs()).To(ConsistOf(HaveField("Spec.Template.Spec.Containers",
+				ContainElement(HaveField("Command", apply.Command)))))
+		})
+
+		It("should hand the stdin Secret over to the Job", func() {
+			_, err := runner.ExecInPod(ctx, pod, ExecRequest{
+				Command: []string{"python", "manage.py", "shell"},
+				Stdin:   []byte("{}"),
+				Owner:   "uid-1",
+			})
+			Expect(jobRunning(err)).To(BeTrue())
+			secrets, err := cs.CoreV1().Secrets("default").List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(secrets.Items).To(HaveLen(1))
+			Expect(secrets.Items[0].OwnerReferences).To(ConsistOf(HaveField("Name", jobs()[0].Name)))
+		})
+	})
+})
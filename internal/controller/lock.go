@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+const (
+	// migrationLockName is the Lease serialising migrations and collectstatic in a namespace.
+	migrationLockName = "django-operator-migrations"
+	// lockLeaseDuration is how long a lock outlives its holder, e.g. a crashed operator.
+	lockLeaseDuration = 2 * time.Minute
+	// lockRenewInterval is how often a held lock is renewed while its command runs.
+	lockRenewInterval = 30 * time.Second
+	// lockOwnerAnnotation holds the UID of the CR holding the lock, which labels the Job
+	// running its command.
+	lockOwnerAnnotation = "django.djangooperator/holder-uid"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch
+
+// namespaceLock is a Lease-based lock held by the CR running an exclusive command in a
+// namespace. Holders are identified as Kind/name, and the UID of the CR is recorded so
+// that the lock stays in use while a Job runs its command, even if no operator renews it.
+type namespaceLock struct {
+	client.Client
+}
+
+// tryAcquire takes or renews the lock of namespace for holder, the CR with UID owner,
+// reporting false if someone else holds it.
+func (l namespaceLock) tryAcquire(ctx context.Context, namespace, holder string, owner types.UID) (bool, error) {
+	now := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{}
+	err := l.Get(ctx, client.ObjectKey{Namespace: namespace, Name: migrationLockName}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        migrationLockName,
+				Namespace:   namespace,
+				Annotations: map[string]string{lockOwnerAnnotation: string(owner)},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(holder),
+				LeaseDurationSeconds: ptr.To(int32(lockLeaseDuration / time.Second)),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := l.Create(ctx, lease); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	current := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if current != holder && current != "" {
+		if !leaseExpired(lease, now.Time) {
+			return false, nil
+		}
+		// The operator renewing the lease may be gone while the Job it launched still runs
+		running, err := l.jobRunning(ctx, namespace, types.UID(lease.Annotations[lockOwnerAnnotation]))
+		if err != nil || running {
+			return false, err
+		}
+	}
+	if current != holder {
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.HolderIdentity = ptr.To(holder)
+	metav1.SetMetaDataAnnotation(&lease.ObjectMeta, lockOwnerAnnotation, string(owner))
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(lockLeaseDuration / time.Second))
+	lease.Spec.RenewTime = &now
+	if err := l.Update(ctx, lease); err != nil {
+		if apierrors.IsConflict(err) {
+			// Someone else got there first
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// jobRunning reports whether a Job launched for the CR with UID owner is still running.
+func (l namespaceLock) jobRunning(ctx context.Context, namespace string, owner types.UID) (bool, error) {
+	if owner == "" {
+		return false, nil
+	}
+	var jobs batchv1.JobList
+	if err := l.List(ctx, &jobs, client.InNamespace(namespace),
+		client.MatchingLabels{jobOwnerLabel: string(owner)}); err != nil {
+		return false, err
+	}
+	for i := range jobs.Items {
+		if jobFinished(&jobs.Items[i]) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// release frees the lock of namespace if holder holds it.
+func (l namespaceLock) release(ctx context.Context, namespace, holder string) error {
+	lease := &coordinationv1.Lease{}
+	if err := l.Get(ctx, client.ObjectKey{Namespace: namespace, Name: migrationLockName}, lease); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != holder {
+		return nil
+	}
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	delete(lease.Annotations, lockOwnerAnnotation)
+	if err := l.Update(ctx, lease); err != nil && !apierrors.IsConflict(err) {
+		return client.IgnoreNotFound(err)
+	}
+	return nil
+}
+
+// hold renews the lock held by holder, the CR with UID owner, until the returned
+// function is called, which then releases it if asked to. A lock kept past that expires
+// unless renewed again, or its holder's Job still runs.
+func (l namespaceLock) hold(namespace, holder string, owner types.UID) func(release bool) {
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockRenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, _ = l.tryAcquire(context.Background(), namespace, holder, owner)
+			}
+		}
+	}()
+	return func(release bool) {
+		close(done)
+		<-stopped
+		if release {
+			// Use a fresh context so the lock is released even if the reconcile's was cancelled
+			_ = l.release(context.Background(), namespace, holder)
+		}
+	}
+}
+
+// leaseExpired reports whether the holder of lease stopped renewing it.
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+// lockQueue returns the exclusive commands of namespace waiting to run, oldest first.
+func lockQueue(ctx context.Context, c client.Reader, namespace string, now time.Time) ([]CommandObject, error) {
+	var migrations djangov1alpha1.DjangoMigrateList
+	if err := c.List(ctx, &migrations, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var statics djangov1alpha1.DjangoStaticList
+	if err := c.List(ctx, &statics, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	var queue []CommandObject
+	for i := range migrations.Items {
+		queue = append(queue, &migrations.Items[i])
+	}
+	for i := range statics.Items {
+		queue = append(queue, &statics.Items[i])
+	}
+
+	waiting := queue[:0]
+	for _, obj := range queue {
+		if queued(obj, now) {
+			waiting = append(waiting, obj)
+		}
+	}
+	sort.SliceStable(waiting, func(i, j int) bool {
+		ti, tj := waiting[i].GetCreationTimestamp(), waiting[j].GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return waiting[i].GetName() < waiting[j].GetName()
+	})
+	return waiting, nil
+}
+
+// queued reports whether obj waits to run: it is neither finished, nor waiting for
+// approval, nor backing off after a failure.
+func queued(obj CommandObject, now time.Time) bool {
+	st := obj.GetCommandStatus()
+	if !obj.GetDeletionTimestamp().IsZero() || finished(obj) ||
+		st.Phase == djangov1alpha1.PhaseSucceeded || st.Phase == djangov1alpha1.PhaseFailed {
+		return false
+	}
+	if st.NextRetryTime != nil && st.NextRetryTime.After(now) {
+		return false
+	}
+	cond := meta.FindStatusCondition(st.Conditions, djangov1alpha1.ConditionProgressing)
+	return cond == nil || cond.Reason != ReasonAwaitingApproval
+}
+
+// finished reports whether obj completed, as its kind's Done check sees it. CRs that
+// finished before the phase was recorded have no phase, but their reconcile returns
+// early and would never release the queue.
+func finished(obj CommandObject) bool {
+	if obj.GetCommandStatus().CompletionTime != nil {
+		return true
+	}
+	switch o := obj.(type) {
+	case *djangov1alpha1.DjangoMigrate:
+		return !o.Status.Applied.IsZero()
+	case *djangov1alpha1.DjangoStatic:
+		return !o.Status.Collected.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("Migration lock", func() {
+	Context("When deciding whether a command waits for the lock", func() {
+		It("should skip finished, approving and backing off commands", func() {
+			now := time.Now()
+			dm := &djangov1alpha1.DjangoMigrate{}
+			Expect(queued(dm, now)).To(BeTrue())
+
+			dm.Status.Phase = djangov1alpha1.PhaseSucceeded
+			Expect(queued(dm, now)).To(BeFalse())
+
+			dm.Status.Phase = djangov1alpha1.PhasePending
+			dm.Status.NextRetryTime = &metav1.Time{Time: now.Add(time.Minute)}
+			Expect(queued(dm, now)).To(BeFalse())
+			Expect(queued(dm, now.Add(2*time.Minute))).To(BeTrue())
+
+			dm.Status.NextRetryTime = nil
+			markPending(&dm.Status.CommandStatus, 1, ReasonAwaitingApproval, "approve me")
+			Expect(queued(dm, now)).To(BeFalse())
+			markPending(&dm.Status.CommandStatus, 1, ReasonWaitingForLock, msgWaitingForLock)
+			Expect(queued(dm, now)).To(BeTrue())
+		})
+
+		It("should skip commands that finished before the phase was recorded", func() {
+			now := time.Now()
+			applied := metav1.NewTime(now.Add(-time.Hour))
+			dm := &djangov1alpha1.DjangoMigrate{}
+			dm.Status.Applied = &applied
+			Expect(queued(dm, now)).To(BeFalse())
+
+			ds := &djangov1alpha1.DjangoStatic{}
+			Expect(queued(ds, now)).To(BeTrue())
+			ds.Status.Collected = &applied
+			Expect(queued(ds, now)).To(BeFalse())
+
+			ds = &djangov1alpha1.DjangoStatic{}
+			ds.Status.CompletionTime = &applied
+			Expect(queued(ds, now)).To(BeFalse())
+		})
+
+		It("should expire leases that are not renewed", func() {
+			renewed := metav1.NewMicroTime(time.Now())
+			lease := &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{
+				RenewTime:            &renewed,
+				LeaseDurationSeconds: ptr.To[int32](60),
+			}}
+			Expect(leaseExpired(lease, renewed.Add(30*time.Second))).To(BeFalse())
+			Expect(leaseExpired(lease, renewed.Add(90*time.Second))).To(BeTrue())
+			Expect(leaseExpired(&coordinationv1.Lease{}, renewed.Time)).To(BeTrue())
+		})
+	})
+
+	Context("When taking the lock of a namespace", func() {
+		const namespace = "lock-acquire"
+		ctx := context.Background()
+		lock := namespaceLock{}
+
+		BeforeEach(func() {
+			lock.Client = k8sClient
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: migrationLockName, Namespace: namespace},
+			}))).To(Succeed())
+		})
+
+		It("should be held by a single holder at a time", func() {
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoMigrate/a", "uid-a")).To(BeTrue())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeFalse())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoMigrate/a", "uid-a")).To(BeTrue())
+
+			By("releasing it")
+			Expect(lock.release(ctx, namespace, "DjangoStatic/b")).To(Succeed())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeFalse())
+			Expect(lock.release(ctx, namespace, "DjangoMigrate/a")).To(Succeed())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeTrue())
+		})
+
+		It("should take over an expired lease", func() {
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoMigrate/a", "uid-a")).To(BeTrue())
+			lease := &coordinationv1.Lease{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: migrationLockName, Namespace: namespace}, lease)).To(Succeed())
+			lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now().Add(-2 * lockLeaseDuration)))
+			Expect(k8sClient.Update(ctx, lease)).To(Succeed())
+
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeTrue())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: migrationLockName, Namespace: namespace}, lease)).To(Succeed())
+			Expect(lease.Spec.HolderIdentity).To(Equal(ptr.To("DjangoStatic/b")))
+			Expect(lease.Spec.LeaseTransitions).To(Equal(ptr.To[int32](1)))
+		})
+
+		It("should not take over an expired lease while its holder's Job runs", func() {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "django-command-a",
+					Namespace: namespace,
+					Labels:    map[string]string{jobOwnerLabel: "uid-a"},
+				},
+				Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{{Name: jobContainerName, Image: "django"}},
+				}}},
+			}
+			Expect(k8sClient.Create(ctx, job)).To(Succeed())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoMigrate/a", "uid-a")).To(BeTrue())
+
+			By("expiring the lease, e.g. while the operator restarts")
+			lease := &coordinationv1.Lease{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: migrationLockName, Namespace: namespace}, lease)).To(Succeed())
+			lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(time.Now().Add(-2 * lockLeaseDuration)))
+			Expect(k8sClient.Update(ctx, lease)).To(Succeed())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeFalse())
+
+			By("removing the Job")
+			Expect(k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))).To(Succeed())
+			Expect(lock.tryAcquire(ctx, namespace, "DjangoStatic/b", "uid-b")).To(BeTrue())
+		})
+	})
+
+	Context("When several commands wait for the lock", func() {
+		const namespace = "lock-queue"
+		ctx := context.Background()
+		// Creation timestamps have a one second resolution, so names break the tie
+		staticName := types.NamespacedName{Name: "a-static", Namespace: namespace}
+		migrateName := types.NamespacedName{Name: "b-migrate", Namespace: namespace}
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoStatic{
+				ObjectMeta: metav1.ObjectMeta{Name: staticName.Name, Namespace: namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{Name: migrateName.Name, Namespace: namespace},
+			})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoStatic{
+				ObjectMeta: metav1.ObjectMeta{Name: staticName.Name, Namespace: namespace},
+			})).To(Succeed())
+			Expect(k8sClient.Delete(ctx, &djangov1alpha1.DjangoMigrate{
+				ObjectMeta: metav1.ObjectMeta{Name: migrateName.Name, Namespace: namespace},
+			})).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: migrationLockName, Namespace: namespace},
+			}))).To(Succeed())
+		})
+
+		It("should run them one at a time, oldest first", func() {
+			migrations := &recordingPodRunner{}
+			migrateReconciler := &DjangoMigrateReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   migrations,
+			}
+			staticReconciler := &DjangoStaticReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Pods:   testPodRunner{},
+			}
+
+			By("queueing the newer migration behind the collectstatic")
+			result, err := migrateReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrateName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(migrations.requests).To(BeEmpty())
+			dm := &djangov1alpha1.DjangoMigrate{}
+			Expect(k8sClient.Get(ctx, migrateName, dm)).To(Succeed())
+			Expect(dm.Status.Phase).To(Equal(djangov1alpha1.PhasePending))
+			cond := meta.FindStatusCondition(dm.Status.Conditions, djangov1alpha1.ConditionProgressing)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(ReasonWaitingForLock))
+
+			By("running the collectstatic and releasing the lock")
+			_, err = staticReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: staticName})
+			Expect(err).NotTo(HaveOccurred())
+			ds := &djangov1alpha1.DjangoStatic{}
+			Expect(k8sClient.Get(ctx, staticName, ds)).To(Succeed())
+			Expect(ds.Status.Phase).To(Equal(djangov1alpha1.PhaseSucceeded))
+			lease := &coordinationv1.Lease{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: migrationLockName, Namespace: namespace}, lease)).To(Succeed())
+			Expect(lease.Spec.HolderIdentity).To(BeNil())
+
+			By("running the migration once it is first in line")
+			_, err = migrateReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: migrateName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrations.requests).NotTo(BeEmpty())
+		})
+	})
+})
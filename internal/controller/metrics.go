@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Values of the result label of the command metrics.
+const (
+	resultSucceeded = "succeeded"
+	resultFailed    = "failed"
+)
+
+// Values of the reason label of podDiscoveryFailuresTotal.
+const (
+	discoveryNoReadyPod = "no_ready_pod"
+	discoveryError      = "error"
+)
+
+var (
+	// commandExecutionsTotal counts command runs by kind and result.
+	commandExecutionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "django_operator_command_executions_total",
+		Help: "Number of management commands run, by CR kind and result",
+	}, []string{"kind", "result"})
+
+	// commandDurationSeconds observes how long command runs take.
+	commandDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "django_operator_command_duration_seconds",
+		Help:    "Duration of management command runs, by CR kind and result",
+		Buckets: []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"kind", "result"})
+
+	// podDiscoveryFailuresTotal counts the times no pod could be found to run a command in.
+	podDiscoveryFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "django_operator_pod_discovery_failures_total",
+		Help: "Number of times no ready Django pod could be found to run a command in, by CR kind and reason",
+	}, []string{"kind", "reason"})
+
+	// prunedCRsTotal counts the CRs deleted by the retention controller.
+	prunedCRsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "django_operator_pruned_crs_total",
+		Help: "Number of finished CRs deleted by the retention controller",
+	}, []string{"kind"})
+
+	// lastMigration reports the age of the last successful migration of every namespace.
+	lastMigration = newMigrationAgeCollector(time.Now)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		commandExecutionsTotal,
+		commandDurationSeconds,
+		podDiscoveryFailuresTotal,
+		prunedCRsTotal,
+		lastMigration,
+	)
+}
+
+// recordExecution records a command run of kind that took d and failed if err is set.
+func recordExecution(kind string, d time.Duration, err error) {
+	result := resultSucceeded
+	if err != nil {
+		result = resultFailed
+	}
+	commandExecutionsTotal.WithLabelValues(kind, result).Inc()
+	commandDurationSeconds.WithLabelValues(kind, result).Observe(d.Seconds())
+}
+
+// migrationAgeCollector exports the time since the last successful migration of every
+// namespace. The age is computed when scraped, so it keeps growing between migrations.
+type migrationAgeCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func newMigrationAgeCollector(now func() time.Time) *migrationAgeCollector {
+	return &migrationAgeCollector{
+		desc: prometheus.NewDesc(
+			"django_operator_last_successful_migration_age_seconds",
+			"Seconds since the last successful DjangoMigrate of the namespace",
+			[]string{"namespace"}, nil,
+		),
+		now:  now,
+		last: map[string]time.Time{},
+	}
+}
+
+// observe records a successful migration in namespace at t.
+func (c *migrationAgeCollector) observe(namespace string, t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.last[namespace]) {
+		c.last[namespace] = t
+	}
+}
+
+func (c *migrationAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *migrationAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for ns, t := range c.last {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(t).Seconds(), ns)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	It("should count and time executions by kind and result", func() {
+		succeeded := testutil.ToFloat64(commandExecutionsTotal.WithLabelValues("MetricsTest", resultSucceeded))
+		failed := testutil.ToFloat64(commandExecutionsTotal.WithLabelValues("MetricsTest", resultFailed))
+
+		recordExecution("MetricsTest", 2*time.Second, nil)
+		recordExecution("MetricsTest", time.Second, errors.New("exit code 1"))
+		recordExecution("MetricsTest", time.Second, errors.New("exit code 1"))
+
+		Expect(testutil.ToFloat64(commandExecutionsTotal.WithLabelValues("MetricsTest", resultSucceeded))).To(Equal(succeeded + 1))
+		Expect(testutil.ToFloat64(commandExecutionsTotal.WithLabelValues("MetricsTest", resultFailed))).To(Equal(failed + 2))
+		Expect(testutil.CollectAndCount(commandDurationSeconds, "django_operator_command_duration_seconds")).To(BeNumerically(">=", 2))
+	})
+
+	It("should report the age of the last successful migration per namespace", func() {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		c := newMigrationAgeCollector(func() time.Time { return now })
+		c.observe("shop", now.Add(-time.Hour))
+		c.observe("shop", now.Add(-2*time.Hour)) // older than the last one, ignored
+		c.observe("blog", now.Add(-time.Minute))
+
+		expected := `
+# HELP django_operator_last_successful_migration_age_seconds Seconds since the last successful DjangoMigrate of the namespace
+# TYPE django_operator_last_successful_migration_age_seconds gauge
+django_operator_last_successful_migration_age_seconds{namespace="blog"} 60
+django_operator_last_successful_migration_age_seconds{namespace="shop"} 3600
+`
+		Expect(testutil.CollectAndCompare(c, strings.NewReader(expected))).To(Succeed())
+
+		// The age keeps growing until the next migration
+		now = now.Add(time.Minute)
+		Expect(testutil.CollectAndCompare(c, strings.NewReader(strings.NewReplacer(
+			"} 60", "} 120", "} 3600", "} 3660").Replace(expected)))).To(Succeed())
+	})
+
+	It("should register every collector with the controller-runtime registry", func() {
+		for _, c := range []prometheus.Collector{
+			commandExecutionsTotal, commandDurationSeconds, podDiscoveryFailuresTotal, prunedCRsTotal, lastMigration,
+		} {
+			var already prometheus.AlreadyRegisteredError
+			Expect(errors.As(metrics.Registry.Register(c), &already)).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// CommandObject is a CR of one of the one-shot command kinds.
+type CommandObject interface {
+	client.Object
+	GetCommandOptions() *djangov1alpha1.CommandOptions
+	GetCommandStatus() *djangov1alpha1.CommandStatus
+}
+
+// commandTask supplies what differs between the one-shot command kinds; the shared
+// commandReconciler does the rest.
+type commandTask[T CommandObject] struct {
+	// Kind is the name of the CR kind, e.g. DjangoMigrate
+	Kind string
+	// New returns an empty CR of the kind
+	New func() T
+	// Done reports whether the CR already ran successfully and needs nothing else
+	Done func(obj T) bool
+	// Command builds the request run for the CR. A permanentError fails the CR for
+	// good; other errors are returned to the controller and retried.
+	Command func(ctx context.Context, obj T) (ExecRequest, error)
+	// Succeeded records the kind's own status after a successful run, e.g. its timestamp.
+	// It returns "" once the CR is done. Otherwise the run was an intermediate step, e.g. a
+	// migration plan, that left the CR Pending, and it returns the reason of its event.
+	Succeeded func(obj T, res ExecResult, now metav1.Time) string
+	// Exclusive commands run one at a time per namespace, oldest CR first, holding the
+	// namespace's migration lock
+	Exclusive bool
+}
+
+// permanentError fails a command for good: retrying cannot help until its spec changes.
+type permanentError struct {
+	reason string
+	err    error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// commandReconciler is the reconcile loop shared by the one-shot command kinds: fetch
+// the CR, skip it when done, wait out the retry backoff, find a pod, run the command
+// and record the outcome in the CR's status.
+type commandReconciler[T CommandObject] struct {
+	client.Client
+	Pods   PodRunner
+	Jobs   PodRunner
+	Runner djangov1alpha1.RunnerType
+	// Container is the default container commands run in; empty means the pod's first one
+	Container string
+	Recorder  record.EventRecorder
+	Task      commandTask[T]
+}
+
+func (r commandReconciler[T]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := r.Task.New()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// CR deleted, nothing to do
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if r.Task.Done(obj) {
+		return ctrl.Result{}, nil
+	}
+	// Skip if failed for good
+	if commandFinished(obj.GetCommandStatus(), obj.GetGeneration()) {
+		return ctrl.Result{}, nil
+	}
+	return r.run(ctx, obj)
+}
+
+// run makes an attempt at the command of obj, unless the backoff of its last failure
+// has not expired yet.
+func (r commandReconciler[T]) run(ctx context.Context, obj T) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	events := commandEvents{Reader: r.Client, Recorder: r.Recorder}
+	st, opts, gen := obj.GetCommandStatus(), obj.GetCommandOptions(), obj.GetGeneration()
+	if wait := retryWait(st); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// Build the command
+	req, err := r.Task.Command(ctx, obj)
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		logger.Error(err, "refusing to run command", "kind", r.Task.Kind, "name", obj.GetName())
+		markFailed(st, gen, perm.reason, perm.err)
+		events.emit(ctx, obj, r.Task.Kind, nil, corev1.EventTypeWarning, EventExecFailed, "%v", perm.err)
+		return ctrl.Result{}, r.Status().Update(ctx, obj)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pod, err := r.Pods.FindDjangoPod(ctx, obj.GetNamespace())
+	if err != nil {
+		podDiscoveryFailuresTotal.WithLabelValues(r.Task.Kind, discoveryError).Inc()
+		return ctrl.Result{}, err
+	}
+	if pod == nil {
+		podDiscoveryFailuresTotal.WithLabelValues(r.Task.Kind, discoveryNoReadyPod).Inc()
+		logger.Info("no ready django pod found; retrying shortly")
+		markPending(st, gen, ReasonNoReadyPod, msgNoReadyPod)
+		events.emit(ctx, obj, r.Task.Kind, nil, corev1.EventTypeWarning, EventPodNotFound, msgNoReadyPod)
+		if err := r.Status().Update(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	req.Container, err = containerFor(pod, r.Container, opts.Container)
+	if err != nil {
+		logger.Error(err, "cannot run command", "kind", r.Task.Kind, "name", obj.GetName(), "pod", pod.Name)
+		delay := handleFailure(st, gen, opts.RetryPolicy, ReasonContainerNotFound, err)
+		events.emit(ctx, obj, r.Task.Kind, pod, corev1.EventTypeWarning, EventExecFailed, "%v", err)
+		if uerr := r.Status().Update(ctx, obj); uerr != nil {
+			return ctrl.Result{}, uerr
+		}
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	keepLock := false
+	if r.Task.Exclusive {
+		unlock, err := r.lock(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if unlock == nil {
+			logger.Info("waiting for the migration lock", "kind", r.Task.Kind, "name", obj.GetName())
+			markPending(st, gen, ReasonWaitingForLock, msgWaitingForLock)
+			if err := r.Status().Update(ctx, obj); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		defer func() { unlock(!keepLock) }()
+	}
+
+	// Mark the attempt as running so it is visible while the command lasts. An attempt
+	// already Running is resumed, e.g. the Job of an earlier reconcile, not counted again.
+	if st.Phase != djangov1alpha1.PhaseRunning {
+		markRunning(st, gen, pod.Name)
+		if err := r.Status().Update(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+		events.emit(ctx, obj, r.Task.Kind, pod, corev1.EventTypeNormal, EventExecStarted,
+			"Attempt %d started in pod %s", st.Attempts, pod.Name)
+	}
+
+	// Exec command
+	started := time.Now()
+	runner := opts.Runner
+	if opts.Image != "" {
+		// Only a Job can run another image than the pod's
+		req.Image, runner = opts.Image, djangov1alpha1.RunnerJob
+	}
+	req.Owner = obj.GetUID()
+	res, err := runnerFor(r.Pods, r.Jobs, r.Runner, runner).ExecInPod(ctx, pod, req)
+	if jobRunning(err) {
+		// The lock stays with obj while its Job runs: the next reconcile renews it
+		keepLock = true
+		logger.V(1).Info("waiting for the command to finish", "kind", r.Task.Kind, "name", obj.GetName(), "reason", err.Error())
+		return ctrl.Result{RequeueAfter: jobRequeueInterval}, nil
+	}
+	if !res.Started.IsZero() {
+		started = res.Started
+	}
+	st.Output = commandOutput(res)
+	if err != nil {
+		recordExecution(r.Task.Kind, time.Since(started), err)
+		logger.Error(err, "failed to exec command", "kind", r.Task.Kind, "name", obj.GetName(), "pod", pod.Name)
+		events.emit(ctx, obj, r.Task.Kind, pod, corev1.EventTypeWarning, EventExecFailed,
+			"Attempt %d failed: %v", st.Attempts, err)
+		delay := handleFailure(st, gen, opts.RetryPolicy, ReasonExecFailed, err)
+		if uerr := r.Status().Update(ctx, obj); uerr != nil {
+			return ctrl.Result{}, uerr
+		}
+		// Requeue explicitly instead of returning the error, so the retry policy
+		// rather than the controller's rate limiter decides when to try again
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	if step := r.Task.Succeeded(obj, res, metav1.Now()); step != "" {
+		// Intermediate steps are not runs of the command: they get their own event and
+		// stay out of the execution metrics
+		if err := r.Status().Update(ctx, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+		events.emit(ctx, obj, r.Task.Kind, pod, corev1.EventTypeNormal, step,
+			"Recorded in pod %s", pod.Name)
+		logger.Info("Command step succeeded", "kind", r.Task.Kind, "name", obj.GetName(), "step", step)
+		return ctrl.Result{}, nil
+	}
+	recordExecution(r.Task.Kind, time.Since(started), nil)
+	markSucceeded(st, gen)
+	st.ImageDigest = res.ImageID
+	if err := r.Status().Update(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+	events.emit(ctx, obj, r.Task.Kind, pod, corev1.EventTypeNormal, EventExecSucceeded,
+		"Succeeded in pod %s", pod.Name)
+	logger.Info("Command succeeded", "kind", r.Task.Kind, "name", obj.GetName())
+	return ctrl.Result{}, nil
+}
+
+// lock takes the migration lock of the namespace of obj if obj is the oldest exclusive
+// command waiting for it, or already running. It returns the function to call once done
+// with the lock, as hold does, or nil if obj has to wait.
+func (r commandReconciler[T]) lock(ctx context.Context, obj T) (func(release bool), error) {
+	queue, err := lockQueue(ctx, r.Client, obj.GetNamespace(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	running := obj.GetCommandStatus().Phase == djangov1alpha1.PhaseRunning
+	if !running && len(queue) > 0 && queue[0].GetUID() != obj.GetUID() {
+		return nil, nil
+	}
+	lock := namespaceLock{Client: r.Client}
+	holder := r.Task.Kind + "/" + obj.GetName()
+	acquired, err := lock.tryAcquire(ctx, obj.GetNamespace(), holder, obj.GetUID())
+	if err != nil || !acquired {
+		return nil, err
+	}
+	return lock.hold(obj.GetNamespace(), holder, obj.GetUID()), nil
+}
+
+// podRunners builds the real Exec and Job runners for pods matching label.
+func podRunners(mgr ctrl.Manager, c client.Client, label PodLabel) (PodRunner, PodRunner, error) {
+	// initialize REST config & clientset
+	restCfg := mgr.GetConfig()
+	cs, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods := DjangoPodRunner{
+		Client:    c,
+		RESTCfg:   restCfg,
+		Clientset: cs,
+		Label:     label,
+	}
+	return pods, JobPodRunner{DjangoPodRunner: pods}, nil
+}
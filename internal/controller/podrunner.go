@@ -1,35 +1,84 @@
 package controller
 
 import (
+	"bytes"
 	"context"
-	"os"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// maxExecOutputBytes bounds how much of each output stream ExecInPod keeps in memory.
+const maxExecOutputBytes = 64 * 1024
+
 type PodLabel map[string]string
 
+// ExecResult is the captured outcome of a command run inside a pod.
+type ExecResult struct {
+	// Stdout and Stderr hold the tail of each stream, at most maxExecOutputBytes long.
+	Stdout string
+	Stderr string
+	// ExitCode is the exit status of the command, -1 if it could not be determined.
+	ExitCode int
+	// ImageID is the image digest of the container the command ran in, if known.
+	ImageID string
+	// Started is when the command started, if known. It can predate the ExecInPod call
+	// that returned the result, e.g. for a Job picked up again.
+	Started time.Time
+}
+
+// ExecRequest describes a command to run against a Django pod.
+type ExecRequest struct {
+	// Container is the name of the container to run the command in. Empty means the
+	// pod's first container.
+	Container string
+	Command   []string
+	// Env is added to the environment of the command.
+	Env []corev1.EnvVar
+	// Image, if set, replaces the image of the container. Only JobPodRunner honours it.
+	Image string
+	// Stdin, if set, is streamed to the command's standard input. Use it for secrets
+	// rather than passing them as arguments.
+	Stdin []byte
+	// Owner is the UID of the CR the command runs for. JobPodRunner labels its Job with
+	// it to pick the Job up again on a later call.
+	Owner types.UID
+}
+
 type PodRunner interface {
 	FindDjangoPod(ctx context.Context, namespace string) (*corev1.Pod, error)
-	ExecInPod(ctx context.Context, pod *corev1.Pod, command []string) error
+	ExecInPod(ctx context.Context, pod *corev1.Pod, req ExecRequest) (ExecResult, error)
 }
 
 type DjangoPodRunner struct {
 	Client    client.Client
 	RESTCfg   *rest.Config
-	Clientset *kubernetes.Clientset
+	Clientset kubernetes.Interface
 	Label     PodLabel
 }
 
-// Returns the first Pod that matches the label
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+// FindDjangoPod returns a Running, Ready and non-terminating Pod that matches the
+// label, preferring pods of the newest ReplicaSet revision. It returns nil if no pod
+// qualifies.
 func (r DjangoPodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.Pod, error) {
-	// Find the Django pod in this namespace
+	// Find the Django pods in this namespace
 	podList := &corev1.PodList{}
 	sel := labels.SelectorFromSet(labels.Set(r.Label))
 	if err := r.Client.List(ctx, podList, &client.ListOptions{
@@ -38,16 +87,88 @@ func (r DjangoPodRunner) FindDjangoPod(ctx context.Context, ns string) (*corev1.
 	}); err != nil {
 		return nil, err
 	}
-	if len(podList.Items) == 0 {
-		return nil, nil
+
+	// Look up the revision of the ReplicaSets owning ready pods, so that during a
+	// rollout commands run against the new version of the code
+	revisions := map[string]int64{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		rsName := replicaSetOwner(pod)
+		if rsName == "" || !podReady(pod) {
+			continue
+		}
+		if _, seen := revisions[rsName]; seen {
+			continue
+		}
+		rs := &appsv1.ReplicaSet{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ns, Name: rsName}, rs); err != nil {
+			if apierrors.IsNotFound(err) {
+				revisions[rsName] = 0
+				continue
+			}
+			return nil, err
+		}
+		rev, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		revisions[rsName] = rev
 	}
-	pod := podList.Items[0]
 
-	return &pod, nil
+	return selectDjangoPod(podList.Items, revisions), nil
+}
+
+// revisionAnnotation is set by the Deployment controller on every ReplicaSet it owns.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// selectDjangoPod picks the ready pod with the highest ReplicaSet revision, breaking
+// ties by picking the newest pod.
+func selectDjangoPod(pods []corev1.Pod, revisions map[string]int64) *corev1.Pod {
+	var candidates []*corev1.Pod
+	for i := range pods {
+		if podReady(&pods[i]) {
+			candidates = append(candidates, &pods[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := revisions[replicaSetOwner(candidates[i])], revisions[replicaSetOwner(candidates[j])]
+		if ri != rj {
+			return ri > rj
+		}
+		return candidates[j].CreationTimestamp.Before(&candidates[i].CreationTimestamp)
+	})
+	pod := *candidates[0]
+	return &pod
 }
 
-// execInPod runs the given command in the first container of the pod
-func (r DjangoPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, command []string) error {
+// podReady reports whether pod is Running, Ready and not being deleted.
+func podReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// replicaSetOwner returns the name of the ReplicaSet controlling pod, if any.
+func replicaSetOwner(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "ReplicaSet" {
+		return owner.Name
+	}
+	return ""
+}
+
+// ExecInPod runs the requested command in the pod and returns the tail of its
+// stdout/stderr together with its exit code.
+func (r DjangoPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, er ExecRequest) (ExecResult, error) {
+	container, err := containerFor(pod, er.Container, "")
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
 	req := r.Clientset.CoreV1().RESTClient().
 		Post().
 		Resource("pods").
@@ -55,9 +176,9 @@ func (r DjangoPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, command
 		Namespace(pod.Namespace).
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
-			Command:   command,
-			Container: pod.Spec.Containers[0].Name,
-			Stdin:     false,
+			Command:   withEnv(er.Command, er.Env),
+			Container: container,
+			Stdin:     er.Stdin != nil,
 			Stdout:    true,
 			Stderr:    true,
 			TTY:       false,
@@ -65,10 +186,102 @@ func (r DjangoPodRunner) ExecInPod(ctx context.Context, pod *corev1.Pod, command
 
 	executor, err := remotecommand.NewSPDYExecutor(r.RESTCfg, "POST", req.URL())
 	if err != nil {
-		return err
+		return ExecResult{ExitCode: -1}, err
 	}
-	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
-	})
+	stdout := &tailBuffer{limit: maxExecOutputBytes}
+	stderr := &tailBuffer{limit: maxExecOutputBytes}
+	opts := remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	}
+	if er.Stdin != nil {
+		opts.Stdin = bytes.NewReader(er.Stdin)
+	}
+	err = executor.StreamWithContext(ctx, opts)
+	return ExecResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: exitCode(err),
+		ImageID:  imageID(pod, container),
+	}, err
+}
+
+// imageID returns the image digest the named container of pod runs, empty if unknown.
+func imageID(pod *corev1.Pod, container string) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container {
+			return cs.ImageID
+		}
+	}
+	return ""
+}
+
+// containerFor returns the name of the container commands run in: the CR override,
+// then the operator default, then the pod's first container. It fails if the named
+// container does not exist in pod.
+func containerFor(pod *corev1.Pod, def, override string) (string, error) {
+	name := def
+	if override != "" {
+		name = override
+	}
+	if name == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return "", nil
+		}
+		return pod.Spec.Containers[0].Name, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("container %q not found in pod %s", name, pod.Name)
+}
+
+// exitCode maps the error returned by a remote command to its exit status.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+	return -1
+}
+
+// tailBuffer is an io.Writer that only retains the last `limit` bytes written to it.
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if n >= t.limit {
+		t.buf = append(t.buf[:0], p[n-t.limit:]...)
+		return n, nil
+	}
+	if over := len(t.buf) + n - t.limit; over > 0 {
+		t.buf = t.buf[:copy(t.buf, t.buf[over:])]
+	}
+	t.buf = append(t.buf, p...)
+	return n, nil
+}
+
+func (t *tailBuffer) String() string {
+	return string(t.buf)
+}
+
+// withEnv prefixes command with env(1) so it runs with the given variables set, as
+// exec cannot set the environment of the process it starts.
+func withEnv(command []string, env []corev1.EnvVar) []string {
+	if len(env) == 0 {
+		return command
+	}
+	out := []string{"env"}
+	for _, e := range env {
+		out = append(out, e.Name+"="+e.Value)
+	}
+	return append(out, command...)
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// djangoPod builds a pod owned by the given ReplicaSet, created `age` ago.
+func djangoPod(name, rs string, age time.Duration, phase corev1.PodPhase, ready bool) corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       rs,
+				Controller: ptr.To(true),
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}},
+		},
+	}
+}
+
+var _ = Describe("DjangoPodRunner", func() {
+	It("should not select pods that are not Running, Ready or are terminating", func() {
+		terminating := djangoPod("terminating", "rs-1", time.Minute, corev1.PodRunning, true)
+		terminating.DeletionTimestamp = ptr.To(metav1.Now())
+		pods := []corev1.Pod{
+			djangoPod("pending", "rs-1", time.Minute, corev1.PodPending, false),
+			djangoPod("crashloop", "rs-1", time.Minute, corev1.PodRunning, false),
+			terminating,
+		}
+		Expect(selectDjangoPod(pods, nil)).To(BeNil())
+
+		pods = append(pods, djangoPod("ready", "rs-1", time.Hour, corev1.PodRunning, true))
+		Expect(selectDjangoPod(pods, nil).Name).To(Equal("ready"))
+	})
+
+	It("should prefer the newest ReplicaSet revision, then the newest pod", func() {
+		pods := []corev1.Pod{
+			djangoPod("old-rs-young", "rs-old", time.Second, corev1.PodRunning, true),
+			djangoPod("new-rs-old", "rs-new", time.Hour, corev1.PodRunning, true),
+			djangoPod("new-rs-young", "rs-new", time.Minute, corev1.PodRunning, true),
+		}
+		revisions := map[string]int64{"rs-old": 3, "rs-new": 4}
+		Expect(selectDjangoPod(pods, revisions).Name).To(Equal("new-rs-young"))
+	})
+	It("should pick the configured container", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "django-abc"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "istio-proxy"}, {Name: "django"},
+			}},
+		}
+		Expect(containerFor(pod, "", "")).To(Equal("istio-proxy"))
+		Expect(containerFor(pod, "django", "")).To(Equal("django"))
+		Expect(containerFor(pod, "missing", "django")).To(Equal("django"))
+		_, err := containerFor(pod, "missing", "")
+		Expect(err).To(MatchError(ContainSubstring(`container "missing" not found`)))
+	})
+
+	It("should set the requested env through env(1)", func() {
+		cmd := []string{"python", "manage.py", "clearsessions"}
+		Expect(withEnv(cmd, nil)).To(Equal(cmd))
+		Expect(withEnv(cmd, []corev1.EnvVar{{Name: "DEBUG", Value: "1"}})).To(Equal(
+			[]string{"env", "DEBUG=1", "python", "manage.py", "clearsessions"}))
+	})
+})
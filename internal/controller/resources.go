@@ -3,12 +3,13 @@ package controller
 import (
 	"context"
 	"sort"
+	"time"
 
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // listCRs returns all objects of the given GVK in namespace, sorted newest-first.
@@ -26,49 +27,100 @@ func listCRs(
 		return nil, err
 	}
 
-	// sort descending by CreationTimestamp
-	sort.Slice(ul.Items, func(i, j int) bool {
-		return ul.Items[i].GetCreationTimestamp().After(
-			ul.Items[j].GetCreationTimestamp().Time,
+	sortNewestFirst(ul.Items)
+	return ul.Items, nil
+}
+
+// sortNewestFirst sorts items descending by CreationTimestamp.
+func sortNewestFirst(items []unstructured.Unstructured) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().After(
+			items[j].GetCreationTimestamp().Time,
 		)
 	})
-
-	return ul.Items, nil
 }
 
-// pruneOldCRs deletes everything except the `keep` newest instances of the given GVK.
-func pruneOldCRs(
-	c client.Client,
-	ctx context.Context,
-	gvk schema.GroupVersionKind,
-	namespace string,
-	keep int,
-) error {
+// expiredCRs returns the items, sorted newest-first, that their retention policy no longer
+// keeps. Pending and Running CRs, and CRs holding finalizers, are never returned.
+func expiredCRs(
+	items []unstructured.Unstructured,
+	defaults djangov1alpha1.RetentionPolicy,
+	now time.Time,
+) []unstructured.Unstructured {
+	var expired []unstructured.Unstructured
+	// position of each item among the finished CRs of the same phase, newest first
+	seen := map[djangov1alpha1.CommandPhase]int32{}
+	for _, u := range items {
+		if _, ok := u.GetLabels()[autoMigrateLabel]; ok {
+			// Trimmed by AutoMigrateReconciler, which needs the last one
+			continue
+		}
+		if _, ok := u.GetLabels()[pipelineLabel]; ok {
+			// Deleted along with their DjangoPipeline
+			continue
+		}
+		phase := djangov1alpha1.CommandPhase(stringField(u, "status", "phase"))
+		if phase != djangov1alpha1.PhaseSucceeded && phase != djangov1alpha1.PhaseFailed {
+			continue
+		}
+		seen[phase]++
+		if len(u.GetFinalizers()) > 0 || u.GetDeletionTimestamp() != nil {
+			continue
+		}
 
-	logger := logf.FromContext(ctx)
-	items, err := listCRs(c, ctx, gvk, namespace)
-	if err != nil {
-		return err
+		policy := retentionFor(u, defaults)
+		limit := policy.SuccessfulHistoryLimit
+		if phase == djangov1alpha1.PhaseFailed {
+			limit = policy.FailedHistoryLimit
+		}
+		switch {
+		case limit != nil && *limit > 0 && seen[phase] > *limit:
+			expired = append(expired, u)
+		case policy.TTLSecondsAfterFinished != nil && *policy.TTLSecondsAfterFinished > 0:
+			ttl := time.Duration(*policy.TTLSecondsAfterFinished) * time.Second
+			if now.Sub(completionTime(u)) >= ttl {
+				expired = append(expired, u)
+			}
+		}
 	}
+	return expired
+}
 
-	// 0 means do not delete CRs
-	if keep < 1 {
-		return nil
+// retentionFor merges the CR's spec.retention over the operator-wide defaults.
+func retentionFor(u unstructured.Unstructured, defaults djangov1alpha1.RetentionPolicy) djangov1alpha1.RetentionPolicy {
+	policy := defaults
+	raw, found, err := unstructured.NestedMap(u.Object, "spec", "retention")
+	if err != nil || !found {
+		return policy
 	}
-
-	if len(items) <= keep {
-		return nil
+	var own djangov1alpha1.RetentionPolicy
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &own); err != nil {
+		return policy
 	}
+	if own.SuccessfulHistoryLimit != nil {
+		policy.SuccessfulHistoryLimit = own.SuccessfulHistoryLimit
+	}
+	if own.FailedHistoryLimit != nil {
+		policy.FailedHistoryLimit = own.FailedHistoryLimit
+	}
+	if own.TTLSecondsAfterFinished != nil {
+		policy.TTLSecondsAfterFinished = own.TTLSecondsAfterFinished
+	}
+	return policy
+}
 
-	// delete the ones after the first `keep`
-	for _, u := range items[keep:] {
-		if err := c.Delete(ctx, &u); err != nil {
-			return err
+// completionTime is status.completionTime, or the creation time for CRs finished before
+// the field existed.
+func completionTime(u unstructured.Unstructured) time.Time {
+	if s := stringField(u, "status", "completionTime"); s != "" {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
 		}
-		logger.Info(
-			"Deleted old CR",
-			"kind", gvk.Kind,
-			"name", u.GetName())
 	}
-	return nil
+	return u.GetCreationTimestamp().Time
+}
+
+func stringField(u unstructured.Unstructured, fields ...string) string {
+	s, _, _ := unstructured.NestedString(u.Object, fields...)
+	return s
 }
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// finishedCR builds an unstructured command CR in the given phase, finished `age` ago.
+func finishedCR(name string, phase djangov1alpha1.CommandPhase, age time.Duration) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"status": map[string]interface{}{
+			"phase":          string(phase),
+			"completionTime": time.Now().Add(-age).UTC().Format(time.RFC3339),
+		},
+	}}
+	return u
+}
+
+func names(items []unstructured.Unstructured) []string {
+	var out []string
+	for _, u := range items {
+		out = append(out, u.GetName())
+	}
+	return out
+}
+
+var _ = Describe("CR retention", func() {
+	// newest first, as returned by listCRs
+	items := func() []unstructured.Unstructured {
+		return []unstructured.Unstructured{
+			finishedCR("running", djangov1alpha1.PhaseRunning, 0),
+			finishedCR("ok-3", djangov1alpha1.PhaseSucceeded, time.Minute),
+			finishedCR("failed-2", djangov1alpha1.PhaseFailed, time.Hour),
+			finishedCR("ok-2", djangov1alpha1.PhaseSucceeded, 2*time.Hour),
+			finishedCR("failed-1", djangov1alpha1.PhaseFailed, 3*time.Hour),
+			finishedCR("ok-1", djangov1alpha1.PhaseSucceeded, 4*time.Hour),
+		}
+	}
+
+	It("should keep everything without a policy", func() {
+		Expect(expiredCRs(items(), djangov1alpha1.RetentionPolicy{}, time.Now())).To(BeEmpty())
+	})
+
+	It("should count succeeded and failed CRs separately", func() {
+		policy := djangov1alpha1.RetentionPolicy{SuccessfulHistoryLimit: ptr.To(int32(1))}
+		Expect(names(expiredCRs(items(), policy, time.Now()))).To(Equal([]string{"ok-2", "ok-1"}))
+
+		policy = djangov1alpha1.RetentionPolicy{FailedHistoryLimit: ptr.To(int32(1))}
+		Expect(names(expiredCRs(items(), policy, time.Now()))).To(Equal([]string{"failed-1"}))
+	})
+
+	It("should expire finished CRs after the TTL but never in-flight ones", func() {
+		policy := djangov1alpha1.RetentionPolicy{TTLSecondsAfterFinished: ptr.To(int32(90 * 60))}
+		Expect(names(expiredCRs(items(), policy, time.Now()))).To(Equal([]string{"ok-2", "failed-1", "ok-1"}))
+	})
+
+	It("should let a CR override the operator-wide policy", func() {
+		crs := items()
+		Expect(unstructured.SetNestedField(crs[5].Object, int64(0), "spec", "retention", "successfulHistoryLimit")).To(Succeed())
+		policy := djangov1alpha1.RetentionPolicy{SuccessfulHistoryLimit: ptr.To(int32(1))}
+		Expect(names(expiredCRs(crs, policy, time.Now()))).To(Equal([]string{"ok-2"}))
+	})
+
+	It("should not prune CRs holding finalizers", func() {
+		crs := items()
+		crs[5].SetFinalizers([]string{userFinalizer})
+		policy := djangov1alpha1.RetentionPolicy{SuccessfulHistoryLimit: ptr.To(int32(1))}
+		Expect(names(expiredCRs(crs, policy, time.Now()))).To(Equal([]string{"ok-2"}))
+	})
+})
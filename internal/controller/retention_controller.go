@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultRetentionInterval is how often finished CRs are swept when no interval is set.
+const defaultRetentionInterval = 5 * time.Minute
+
+// RetentionController periodically deletes the finished command CRs that fall outside
+// their retention policy. It runs on its own schedule, so old CRs are cleaned up even
+// when no new ones are created, and pruning errors never fail a command's reconcile.
+type RetentionController struct {
+	client.Client
+	Recorder record.EventRecorder
+	// Namespace is swept; the operator is namespaced so this is the watched namespace
+	Namespace string
+	Interval  time.Duration
+	// Policies holds the operator-wide retention policy of every kind to sweep
+	Policies map[string]djangov1alpha1.RetentionPolicy
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Start sweeps every kind once and then every Interval until ctx is cancelled.
+func (r *RetentionController) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("retention")
+	ctx = logf.IntoContext(ctx, logger)
+
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader prune, like the reconcilers.
+func (r *RetentionController) NeedLeaderElection() bool {
+	return true
+}
+
+// sweep prunes every configured kind, carrying on with the next kind when one fails.
+func (r *RetentionController) sweep(ctx context.Context) {
+	logger := logf.FromContext(ctx)
+	for kind, policy := range r.Policies {
+		if err := r.prune(ctx, kind, policy); err != nil {
+			logger.Error(err, "failed to prune old CRs", "kind", kind)
+		}
+	}
+}
+
+// prune deletes the CRs of one kind that their retention policy no longer keeps.
+func (r *RetentionController) prune(ctx context.Context, kind string, defaults djangov1alpha1.RetentionPolicy) error {
+	logger := logf.FromContext(ctx)
+	gvk := djangov1alpha1.GroupVersion.WithKind(kind)
+	events := commandEvents{Reader: r.Client, Recorder: r.Recorder}
+	items, err := listCRs(r.Client, ctx, gvk, r.Namespace)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range expiredCRs(items, defaults, time.Now()) {
+		if err := r.Delete(ctx, &u); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		prunedCRsTotal.WithLabelValues(kind).Inc()
+		events.emit(ctx, &u, kind, nil, corev1.EventTypeNormal, EventPruned, "Deleted: outside its retention policy")
+		logger.Info(
+			"Deleted old CR",
+			"kind", kind,
+			"name", u.GetName())
+	}
+	return nil
+}
+
+// SetupWithManager adds the retention controller to the Manager as a Runnable.
+func (r *RetentionController) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("django-retention")
+	}
+	return mgr.Add(r)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("RetentionController", func() {
+	ctx := context.Background()
+
+	// createFinished creates a DjangoStatic in the given phase that finished `age` ago.
+	createFinished := func(name string, phase djangov1alpha1.CommandPhase, age time.Duration) {
+		ds := &djangov1alpha1.DjangoStatic{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		Expect(k8sClient.Create(ctx, ds)).To(Succeed())
+		ds.Status.Phase = phase
+		if phase == djangov1alpha1.PhaseSucceeded || phase == djangov1alpha1.PhaseFailed {
+			ds.Status.CompletionTime = ptr.To(metav1.NewTime(time.Now().Add(-age)))
+		}
+		Expect(k8sClient.Status().Update(ctx, ds)).To(Succeed())
+	}
+	exists := func(name string) bool {
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &djangov1alpha1.DjangoStatic{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	AfterEach(func() {
+		Expect(k8sClient.DeleteAllOf(ctx, &djangov1alpha1.DjangoStatic{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should delete expired CRs, emit events and keep in-flight ones", func() {
+		createFinished("retention-old", djangov1alpha1.PhaseSucceeded, 2*time.Hour)
+		createFinished("retention-new", djangov1alpha1.PhaseSucceeded, time.Minute)
+		createFinished("retention-running", djangov1alpha1.PhaseRunning, 0)
+
+		recorder := record.NewFakeRecorder(10)
+		gc := &RetentionController{
+			Client:    k8sClient,
+			Recorder:  recorder,
+			Namespace: "default",
+			Policies: map[string]djangov1alpha1.RetentionPolicy{
+				"DjangoStatic": {TTLSecondsAfterFinished: ptr.To(int32(3600))},
+			},
+		}
+		gc.sweep(ctx)
+
+		Expect(exists("retention-old")).To(BeFalse())
+		Expect(exists("retention-new")).To(BeTrue())
+		Expect(exists("retention-running")).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(EventPruned)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
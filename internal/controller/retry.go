@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+const (
+	defaultBackoffBase = 10 * time.Second
+	defaultBackoffCap  = 5 * time.Minute
+)
+
+// backoffDelay returns the delay before the next attempt after `attempts` failures:
+// base * 2^(attempts-1), capped.
+func backoffDelay(p *djangov1alpha1.RetryPolicy, attempts int32) time.Duration {
+	base, limit := defaultBackoffBase, defaultBackoffCap
+	if p != nil && p.BackoffBase != nil {
+		base = p.BackoffBase.Duration
+	}
+	if p != nil && p.BackoffCap != nil {
+		limit = p.BackoffCap.Duration
+	}
+	delay := base
+	for i := int32(1); i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// handleFailure records a failed attempt in st. It returns the delay before the
+// next attempt, or zero when the retry policy is exhausted and the CR is now Failed.
+func handleFailure(
+	st *djangov1alpha1.CommandStatus,
+	generation int64,
+	p *djangov1alpha1.RetryPolicy,
+	reason string,
+	err error,
+) time.Duration {
+	if p != nil && p.MaxAttempts > 0 && st.Attempts >= p.MaxAttempts {
+		st.NextRetryTime = nil
+		markFailed(st, generation, ReasonMaxAttemptsReached,
+			fmt.Errorf("giving up after %d attempts: %w", st.Attempts, err))
+		return 0
+	}
+	delay := backoffDelay(p, st.Attempts)
+	next := metav1.NewTime(time.Now().Add(delay))
+	st.NextRetryTime = &next
+	markRetrying(st, generation, reason, err, delay)
+	return delay
+}
+
+// retryWait returns how long is left before the next attempt may start.
+func retryWait(st *djangov1alpha1.CommandStatus) time.Duration {
+	if st.NextRetryTime == nil {
+		return 0
+	}
+	return time.Until(st.NextRetryTime.Time)
+}
+
+// commandFinished reports whether a command CR needs no further work: it succeeded, or
+// it failed terminally and its spec has not changed since. A failed CR whose spec was
+// edited is given a fresh set of attempts.
+func commandFinished(st *djangov1alpha1.CommandStatus, generation int64) bool {
+	switch st.Phase {
+	case djangov1alpha1.PhaseSucceeded:
+		return true
+	case djangov1alpha1.PhaseFailed:
+		if st.ObservedGeneration == generation {
+			return true
+		}
+		st.Attempts = 0
+		st.NextRetryTime = nil
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// maxStatusOutputBytes bounds the output stored per stream in a CR status, so a
+// chatty command cannot push the object towards the etcd size limit.
+const maxStatusOutputBytes = 4 * 1024
+
+// Reasons used in the conditions of the one-shot command kinds.
+const (
+	ReasonNoReadyPod = "NoReadyPod"
+	ReasonRunning    = "Running"
+	ReasonSucceeded  = "Succeeded"
+	ReasonExecFailed = "ExecFailed"
+	// ReasonContainerNotFound is set when the configured container is missing from the pod
+	ReasonContainerNotFound = "ContainerNotFound"
+	// ReasonCleanupFailed is set when a DjangoUser's deletion policy could not be applied
+	ReasonCleanupFailed = "CleanupFailed"
+	// ReasonMaxAttemptsReached is set once the retry policy is exhausted
+	ReasonMaxAttemptsReached = "MaxAttemptsReached"
+	// ReasonCommandNotAllowed is set when a DjangoCommand is not in the operator's allowlist
+	ReasonCommandNotAllowed = "CommandNotAllowed"
+	// ReasonAwaitingApproval is set while the plan of a DjangoMigrate waits to be approved
+	ReasonAwaitingApproval = "AwaitingApproval"
+	// ReasonRollbackTargetNotFound is set when spec.rollbackTo names no applied DjangoMigrate
+	ReasonRollbackTargetNotFound = "RollbackTargetNotFound"
+	// ReasonPreApplyRecorded is set between recording the migrations of a DjangoMigrate and
+	// applying it
+	ReasonPreApplyRecorded = "PreApplyRecorded"
+	// ReasonWaitingForLock is set while an older migration or collectstatic of the namespace runs
+	ReasonWaitingForLock = "WaitingForLock"
+)
+
+// Reasons used in the Ready condition of a DjangoCronJob.
+const (
+	ReasonScheduled = "Scheduled"
+	// ReasonInvalidSchedule is set when the schedule cannot be parsed or never fires
+	ReasonInvalidSchedule = "InvalidSchedule"
+	// ReasonInvalidTemplate is set when no run can be built from the template
+	ReasonInvalidTemplate = "InvalidTemplate"
+	// ReasonTooManyMissedRuns is set when more than maxMissedRuns scheduled times were missed
+	ReasonTooManyMissedRuns = "TooManyMissedRuns"
+)
+
+// msgNoReadyPod is the condition message used while no Django pod qualifies for running commands.
+const msgNoReadyPod = "no ready Django pod found"
+
+// msgPreApplyRecorded is the condition message used once the migrations applied before a
+// DjangoMigrate are recorded.
+const msgPreApplyRecorded = "recorded the migrations applied before the run; migrating"
+
+// msgWaitingForLock is the condition message used while another command holds the migration lock.
+const msgWaitingForLock = "waiting for the migration lock of the namespace"
+
+// commandOutput converts an ExecResult into its truncated status representation.
+func commandOutput(res ExecResult) *djangov1alpha1.CommandOutput {
+	return &djangov1alpha1.CommandOutput{
+		ExitCode: int32(res.ExitCode),
+		Stdout:   tail(res.Stdout, maxStatusOutputBytes),
+		Stderr:   tail(res.Stderr, maxStatusOutputBytes),
+	}
+}
+
+// tail returns the last n bytes of s without splitting a UTF-8 sequence.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	for len(s) > 0 && !utf8.RuneStart(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+// setConditions sets the Ready, Progressing and Failed conditions in one go.
+func setConditions(
+	st *djangov1alpha1.CommandStatus,
+	generation int64,
+	ready, progressing, failed metav1.ConditionStatus,
+	reason, message string,
+) {
+	for condType, status := range map[string]metav1.ConditionStatus{
+		djangov1alpha1.ConditionReady:       ready,
+		djangov1alpha1.ConditionProgressing: progressing,
+		djangov1alpha1.ConditionFailed:      failed,
+	} {
+		meta.SetStatusCondition(&st.Conditions, metav1.Condition{
+			Type:               condType,
+			Status:             status,
+			ObservedGeneration: generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
+
+// markPending records that the command is waiting to be started.
+func markPending(st *djangov1alpha1.CommandStatus, generation int64, reason, message string) {
+	st.Phase = djangov1alpha1.PhasePending
+	st.ObservedGeneration = generation
+	setConditions(st, generation, metav1.ConditionFalse, metav1.ConditionTrue, metav1.ConditionFalse, reason, message)
+}
+
+// markRunning records the start of a new attempt.
+func markRunning(st *djangov1alpha1.CommandStatus, generation int64, pod string) {
+	st.Phase = djangov1alpha1.PhaseRunning
+	st.ObservedGeneration = generation
+	st.Attempts++
+	st.NextRetryTime = nil
+	st.CompletionTime = nil
+	setConditions(st, generation, metav1.ConditionFalse, metav1.ConditionTrue, metav1.ConditionFalse,
+		ReasonRunning, "running command in pod "+pod)
+}
+
+// markSucceeded records a successful run.
+func markSucceeded(st *djangov1alpha1.CommandStatus, generation int64) {
+	now := metav1.Now()
+	st.Phase = djangov1alpha1.PhaseSucceeded
+	st.ObservedGeneration = generation
+	st.CompletionTime = &now
+	st.LastError = ""
+	st.NextRetryTime = nil
+	setConditions(st, generation, metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionFalse,
+		ReasonSucceeded, "command completed successfully")
+}
+
+// markRetrying records a failed attempt that will be retried after delay.
+func markRetrying(
+	st *djangov1alpha1.CommandStatus,
+	generation int64,
+	reason string,
+	err error,
+	delay time.Duration,
+) {
+	st.Phase = djangov1alpha1.PhasePending
+	st.ObservedGeneration = generation
+	st.LastError = err.Error()
+	setConditions(st, generation, metav1.ConditionFalse, metav1.ConditionTrue, metav1.ConditionTrue,
+		reason, fmt.Sprintf("%s; retrying in %s", err, delay))
+}
+
+// markFailed records that the command failed and will not be retried.
+func markFailed(st *djangov1alpha1.CommandStatus, generation int64, reason string, err error) {
+	now := metav1.Now()
+	st.Phase = djangov1alpha1.PhaseFailed
+	st.ObservedGeneration = generation
+	st.CompletionTime = &now
+	st.LastError = err.Error()
+	setConditions(st, generation, metav1.ConditionFalse, metav1.ConditionFalse, metav1.ConditionTrue,
+		reason, err.Error())
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// AppValues returns the chart values of app: spec.values with the typed fields of the
+// spec merged over them. defaults, the values of the chart, complete the Celery queues.
+func AppValues(app *djangov1alpha1.DjangoApp, defaults chartutil.Values) (chartutil.Values, error) {
+	vals := map[string]interface{}{}
+	if app.Spec.Values != nil {
+		if err := json.Unmarshal(app.Spec.Values.Raw, &vals); err != nil {
+			return nil, err
+		}
+	}
+	typed, err := typedValues(&app.Spec, defaults)
+	if err != nil {
+		return nil, err
+	}
+	mergeValues(vals, typed)
+	return vals, nil
+}
+
+// typedValues maps the typed fields of spec onto the keys of the chart. Unset fields are
+// left out, so the chart defaults or spec.values apply.
+func typedValues(spec *djangov1alpha1.DjangoAppSpec, defaults chartutil.Values) (map[string]interface{}, error) {
+	typed := map[string]interface{}{}
+	if spec.Image != nil {
+		typed["image"] = spec.Image
+	}
+	if spec.Env != nil {
+		typed["global"] = map[string]interface{}{"env": spec.Env}
+	}
+
+	server := map[string]interface{}{}
+	if spec.Replicas != nil {
+		server["replicaCount"] = *spec.Replicas
+	}
+	if spec.Resources != nil {
+		server["resources"] = spec.Resources
+	}
+	if spec.Ingress != nil {
+		server["ingress"] = spec.Ingress
+	}
+	if spec.Probes != nil {
+		server["probe"] = spec.Probes
+	}
+	if len(server) > 0 {
+		typed["djangoServer"] = server
+	}
+
+	if c := spec.Celery; c != nil {
+		if len(c.Workers) > 0 {
+			typed["celeryWorker"] = map[string]interface{}{"enabled": true, "queue": celeryQueues(c.Workers, defaults)}
+		}
+		if c.Beat != nil {
+			beat := map[string]interface{}{"enabled": true}
+			if c.Beat.Command != nil {
+				beat["command"] = c.Beat.Command
+			}
+			if c.Beat.Resources != nil {
+				beat["resources"] = c.Beat.Resources
+			}
+			typed["celeryBeat"] = beat
+		}
+	}
+
+	// Round trip through JSON so the typed fields become plain values, like spec.values
+	raw, err := json.Marshal(typed)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	return out, json.Unmarshal(raw, &out)
+}
+
+// celeryQueues returns the chart queues of workers. Helm replaces lists rather than
+// merging them, so every queue starts from the first queue of the chart defaults, which
+// carries the probe and security settings the worker template expects.
+func celeryQueues(workers []djangov1alpha1.CeleryWorker, defaults chartutil.Values) []interface{} {
+	var base map[string]interface{}
+	if q, err := defaults.PathValue("celeryWorker.queue"); err == nil {
+		if list, ok := q.([]interface{}); ok && len(list) > 0 {
+			base, _ = list[0].(map[string]interface{})
+		}
+	}
+	queues := make([]interface{}, 0, len(workers))
+	for _, w := range workers {
+		// Shallow copies do: typedValues copies the result through JSON
+		queue := map[string]interface{}{}
+		for k, v := range base {
+			queue[k] = v
+		}
+		queue["name"] = w.Name
+		if w.Replicas != nil {
+			queue["replicaCount"] = *w.Replicas
+		}
+		if w.Command != nil {
+			queue["command"] = w.Command
+		}
+		if w.Resources != nil {
+			queue["resources"] = w.Resources
+		}
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+// mergeValues merges src into dst. Tables are merged key by key; any other value in src,
+// lists included, replaces the one in dst, as Helm does with user values.
+func mergeValues(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcTable, ok := v.(map[string]interface{})
+		dstTable, isTable := dst[k].(map[string]interface{})
+		if ok && isTable {
+			mergeValues(dstTable, srcTable)
+			continue
+		}
+		dst[k] = v
+	}
+}
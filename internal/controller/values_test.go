@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	charts "github.com/jvdiago/django-helm-template"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+var _ = Describe("DjangoApp values", func() {
+	Context("When mapping the typed spec onto the chart", func() {
+		app := &djangov1alpha1.DjangoApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+			Spec: djangov1alpha1.DjangoAppSpec{
+				Image:    &djangov1alpha1.AppImage{Repository: "registry/shop", Tag: "v2"},
+				Replicas: ptr.To[int32](3),
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+				},
+				Env: []corev1.EnvVar{{Name: "DJANGO_DEBUG", Value: "false"}},
+				Ingress: &djangov1alpha1.AppIngress{
+					Enabled: ptr.To(true),
+					Hosts:   []djangov1alpha1.IngressHost{{Host: "shop.example.com", Paths: []string{"/"}}},
+				},
+				Probes: &djangov1alpha1.AppProbes{
+					Readiness: &djangov1alpha1.HTTPProbe{Path: "/healthz", PeriodSeconds: ptr.To[int32](5)},
+				},
+				Celery: &djangov1alpha1.AppCelery{
+					Workers: []djangov1alpha1.CeleryWorker{{Name: "emails", Replicas: ptr.To[int32](2)}},
+					Beat:    &djangov1alpha1.CeleryBeat{},
+				},
+				Values: &apiextv1.JSON{Raw: []byte(`{
+					"image": {"tag": "v1", "pullPolicy": "Always"},
+					"djangoServer": {"replicaCount": 1, "probe": {"enabled": true}},
+					"celeryFlower": {"enabled": true}
+				}`)},
+			},
+		}
+
+		It("should merge the typed fields over spec.values", func() {
+			vals, err := AppValues(app, chartDefaults)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vals["image"]).To(Equal(map[string]interface{}{
+				"repository": "registry/shop", "tag": "v2", "pullPolicy": "Always",
+			}))
+			Expect(vals.PathValue("djangoServer.replicaCount")).To(BeNumerically("==", 3))
+			server := vals["djangoServer"].(map[string]interface{})
+			Expect(server["resources"]).To(Equal(map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "250m"},
+			}))
+			Expect(server["probe"]).To(Equal(map[string]interface{}{
+				"enabled":   true,
+				"readiness": map[string]interface{}{"path": "/healthz", "periodSeconds": float64(5)},
+			}))
+			Expect(vals.PathValue("global.env")).To(Equal([]interface{}{
+				map[string]interface{}{"name": "DJANGO_DEBUG", "value": "false"},
+			}))
+			Expect(vals.PathValue("celeryBeat.enabled")).To(BeTrue())
+			Expect(vals.PathValue("celeryFlower.enabled")).To(BeTrue())
+			Expect(appImage(app, chartDefaults)).To(Equal("registry/shop:v2"))
+		})
+
+		It("should leave spec.values alone without typed fields", func() {
+			plain := &djangov1alpha1.DjangoApp{Spec: djangov1alpha1.DjangoAppSpec{Values: app.Spec.Values}}
+			vals, err := AppValues(plain, chartDefaults)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vals).To(HaveLen(3))
+			Expect(vals.PathValue("image.tag")).To(Equal("v1"))
+		})
+
+		It("should render the embedded chart", func() {
+			chartObj, err := charts.Chart()
+			Expect(err).NotTo(HaveOccurred())
+			vals, err := AppValues(app, chartObj.Values)
+			Expect(err).NotTo(HaveOccurred())
+			queue, err := vals.PathValue("celeryWorker.queue")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queue).To(HaveLen(1))
+			Expect(queue.([]interface{})[0]).To(HaveKeyWithValue("probe", Not(BeNil())))
+
+			renderVals, err := chartutil.ToRenderValues(chartObj, vals,
+				chartutil.ReleaseOptions{Name: app.Name, Namespace: app.Namespace}, chartutil.DefaultCapabilities)
+			Expect(err).NotTo(HaveOccurred())
+			manifests, err := engine.Render(chartObj, renderVals)
+			Expect(err).NotTo(HaveOccurred())
+			var rendered []string
+			for _, m := range manifests {
+				rendered = append(rendered, m)
+			}
+			Expect(rendered).To(ContainElement(ContainSubstring("shop-celery-work-emails")))
+			Expect(rendered).To(ContainElement(ContainSubstring("registry/shop:v2")))
+			Expect(rendered).To(ContainElement(ContainSubstring(`host: "shop.example.com"`)))
+		})
+	})
+})
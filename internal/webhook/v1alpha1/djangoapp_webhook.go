@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	charts "github.com/jvdiago/django-helm-template"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+	"github.com/jvdiago/django-operator/internal/controller"
+)
+
+// schemaURL is the location the values schema is compiled under, as Helm does.
+const schemaURL = "file:///values.schema.json"
+
+// log is for logging in this package.
+var djangoapplog = logf.Log.WithName("djangoapp-resource")
+
+// printer renders the schema errors.
+var printer = message.NewPrinter(language.English)
+
+// valuesSchema is the schema of the values of the embedded chart, which ships none.
+//
+//go:embed values.schema.json
+var valuesSchema []byte
+
+// SetupDjangoAppWebhookWithManager registers the webhook for DjangoApp in the manager.
+func SetupDjangoAppWebhookWithManager(mgr ctrl.Manager) error {
+	chartObj, err := chartWithSchema()
+	if err != nil {
+		return err
+	}
+	validator, err := NewDjangoAppCustomValidator(chartObj)
+	if err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).For(&djangov1alpha1.DjangoApp{}).
+		WithValidator(validator).
+		Complete()
+}
+
+// chartWithSchema loads the embedded chart. Without a values.schema.json of its own it gets
+// the one of the operator, so the webhook always validates.
+func chartWithSchema() (*chart.Chart, error) {
+	chartObj, err := charts.Chart()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded chart: %w", err)
+	}
+	if len(chartObj.Schema) == 0 {
+		djangoapplog.Info("The embedded chart has no values.schema.json, validating with the operator's",
+			"chart", chartObj.Name(), "version", chartObj.Metadata.Version)
+		chartObj.Schema = valuesSchema
+	}
+	return chartObj, nil
+}
+
+// +kubebuilder:webhook:path=/validate-django-djangooperator-v1alpha1-djangoapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=django.djangooperator,resources=djangoapps,verbs=create;update,versions=v1alpha1,name=vdjangoapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// DjangoAppCustomValidator rejects a DjangoApp whose chart values, the typed fields merged
+// over spec.values and those over the chart defaults, do not conform to the
+// values.schema.json of the chart.
+// Without a schema every DjangoApp is admitted.
+type DjangoAppCustomValidator struct {
+	chart  *chart.Chart
+	schema *jsonschema.Schema
+}
+
+var _ webhook.CustomValidator = &DjangoAppCustomValidator{}
+
+// NewDjangoAppCustomValidator compiles the values schema of chrt, if it has one.
+func NewDjangoAppCustomValidator(chrt *chart.Chart) (*DjangoAppCustomValidator, error) {
+	v := &DjangoAppCustomValidator{chart: chrt}
+	if len(chrt.Schema) == 0 {
+		return v, nil
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(chrt.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("parsing values.schema.json of chart %s: %w", chrt.Name(), err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, err
+	}
+	if v.schema, err = compiler.Compile(schemaURL); err != nil {
+		return nil, fmt.Errorf("compiling values.schema.json of chart %s: %w", chrt.Name(), err)
+	}
+	return v, nil
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type DjangoApp.
+func (v *DjangoAppCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	app, ok := obj.(*djangov1alpha1.DjangoApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a DjangoApp object but got %T", obj)
+	}
+	djangoapplog.V(1).Info("Validation for DjangoApp upon creation", "name", app.GetName())
+	return nil, v.validate(app)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type DjangoApp.
+// An unchanged spec is admitted, so an app admitted before the schema tightened can still
+// get its finalizers updated and be deleted.
+func (v *DjangoAppCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	app, ok := newObj.(*djangov1alpha1.DjangoApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a DjangoApp object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*djangov1alpha1.DjangoApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a DjangoApp object for the oldObj but got %T", oldObj)
+	}
+	djangoapplog.V(1).Info("Validation for DjangoApp upon update", "name", app.GetName())
+	if equality.Semantic.DeepEqual(old.Spec, app.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(app)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type DjangoApp.
+func (v *DjangoAppCustomValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// typedPaths maps the chart values set by the typed fields of the spec back to those
+// fields. depth is how many tokens of the location past values to keep, -1 for all.
+var typedPaths = []struct {
+	values []string
+	spec   *field.Path
+	depth  int
+}{
+	{[]string{"image"}, field.NewPath("spec", "image"), -1},
+	{[]string{"global", "env"}, field.NewPath("spec", "env"), -1},
+	{[]string{"djangoServer", "replicaCount"}, field.NewPath("spec", "replicas"), -1},
+	{[]string{"djangoServer", "resources"}, field.NewPath("spec", "resources"), -1},
+	{[]string{"djangoServer", "ingress"}, field.NewPath("spec", "ingress"), -1},
+	{[]string{"djangoServer", "probe"}, field.NewPath("spec", "probes"), -1},
+	// The queue keys differ from the worker fields, so errors point at the worker
+	{[]string{"celeryWorker", "queue"}, field.NewPath("spec", "celery", "workers"), 1},
+	{[]string{"celeryBeat"}, field.NewPath("spec", "celery", "beat"), -1},
+}
+
+// validate returns an Invalid error listing every value of app that violates the schema.
+// It validates the values the chart is installed with: the typed fields merged over
+// spec.values, coalesced with the chart defaults.
+func (v *DjangoAppCustomValidator) validate(app *djangov1alpha1.DjangoApp) error {
+	if v.schema == nil {
+		return nil
+	}
+	path := field.NewPath("spec", "values")
+	invalid := func(errs field.ErrorList) error {
+		return apierrors.NewInvalid(djangov1alpha1.GroupVersion.WithKind("DjangoApp").GroupKind(), app.Name, errs)
+	}
+	if app.Spec.Values != nil {
+		if err := json.Unmarshal(app.Spec.Values.Raw, &map[string]interface{}{}); err != nil {
+			return invalid(field.ErrorList{field.Invalid(path, field.OmitValueType{}, "must be an object")})
+		}
+	}
+	vals, err := controller.AppValues(app, v.chart.Values)
+	if err != nil {
+		return err
+	}
+	// The values of the typed fields alone tell which errors to report against them
+	typedOnly := app.DeepCopy()
+	typedOnly.Spec.Values = nil
+	typed, err := controller.AppValues(typedOnly, v.chart.Values)
+	if err != nil {
+		return err
+	}
+	merged, err := chartutil.CoalesceValues(v.chart, vals)
+	if err != nil {
+		return err
+	}
+	var verr *jsonschema.ValidationError
+	if err := v.schema.Validate(merged.AsMap()); !errors.As(err, &verr) {
+		return err
+	}
+	return invalid(schemaErrors(verr, func(location []string) (*field.Path, interface{}) {
+		p, value := valuePath(path, merged.AsMap(), location)
+		if value == nil {
+			value = field.OmitValueType{}
+		}
+		if tp, ok := typedPath(typed, location); ok {
+			p = tp
+		}
+		return p, value
+	}))
+}
+
+// schemaErrors returns the errors at the leaves of verr, each at the field path and with
+// the value that at returns for the location of the offending value.
+func schemaErrors(verr *jsonschema.ValidationError, at func([]string) (*field.Path, interface{})) field.ErrorList {
+	if len(verr.Causes) == 0 {
+		p, value := at(verr.InstanceLocation)
+		return field.ErrorList{field.Invalid(p, value, verr.ErrorKind.LocalizedString(printer))}
+	}
+	var errs field.ErrorList
+	for _, cause := range verr.Causes {
+		errs = append(errs, schemaErrors(cause, at)...)
+	}
+	return errs
+}
+
+// typedPath returns the path of the typed field that set the value at location, if typed,
+// the values of the typed fields alone, has one there.
+func typedPath(typed map[string]interface{}, location []string) (*field.Path, bool) {
+	for _, tp := range typedPaths {
+		if len(location) < len(tp.values) || !slices.Equal(location[:len(tp.values)], tp.values) {
+			continue
+		}
+		if _, value := valuePath(tp.spec, typed, location); value == nil {
+			return nil, false
+		}
+		rest := location[len(tp.values):]
+		if tp.depth >= 0 && len(rest) > tp.depth {
+			rest = rest[:tp.depth]
+		}
+		// The prefix is all tables, they hold the value
+		var sub interface{} = typed
+		for _, tok := range tp.values {
+			sub = sub.(map[string]interface{})[tok]
+		}
+		p, _ := valuePath(tp.spec, sub, rest)
+		return p, true
+	}
+	return nil, false
+}
+
+// valuePath follows location, a JSON pointer split in tokens, from vals. It returns the
+// field path of the value found there and the value itself, if it is a scalar, nil if
+// there is none.
+func valuePath(path *field.Path, vals interface{}, location []string) (*field.Path, interface{}) {
+	cur := vals
+	for _, tok := range location {
+		switch c := cur.(type) {
+		case []interface{}:
+			i, _ := strconv.Atoi(tok)
+			path, cur = path.Index(i), nil
+			if i < len(c) {
+				cur = c[i]
+			}
+		case map[string]interface{}:
+			path, cur = path.Child(tok), c[tok]
+		default:
+			path, cur = path.Child(tok), nil
+		}
+	}
+	switch cur.(type) {
+	case nil:
+		return path, nil
+	case map[string]interface{}, []interface{}:
+		return path, field.OmitValueType{}
+	}
+	return path, cur
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	djangov1alpha1 "github.com/jvdiago/django-operator/api/v1alpha1"
+)
+
+// schemaChart is a chart whose values.schema.json constrains a few of its values.
+var schemaChart = &chart.Chart{
+	Metadata: &chart.Metadata{Name: "django-helm", Version: "1.2.0"},
+	Values: map[string]interface{}{
+		"image":        map[string]interface{}{"repository": "jvela/sample-django", "tag": "latest"},
+		"djangoServer": map[string]interface{}{"replicaCount": 1},
+	},
+	Schema: []byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["image"],
+		"properties": {
+			"image": {
+				"type": "object",
+				"required": ["repository"],
+				"properties": {
+					"repository": {"type": "string", "minLength": 1},
+					"pullPolicy": {"enum": ["Always", "IfNotPresent", "Never"]}
+				}
+			},
+			"djangoServer": {
+				"type": "object",
+				"properties": {
+					"replicaCount": {"type": "integer", "minimum": 0},
+					"ingress": {
+						"type": "object",
+						"properties": {
+							"hosts": {"type": "array", "items": {"type": "object", "required": ["host"]}}
+						}
+					}
+				}
+			}
+		}
+	}`),
+}
+
+var _ = Describe("DjangoApp Webhook", func() {
+	var (
+		obj       *djangov1alpha1.DjangoApp
+		validator *DjangoAppCustomValidator
+	)
+	withValues := func(raw string) *djangov1alpha1.DjangoApp {
+		obj.Spec.Values = &apiextv1.JSON{Raw: []byte(raw)}
+		return obj
+	}
+	// causes returns the field and message of every cause of the Invalid error err.
+	causes := func(err error) map[string]string {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+		fields := map[string]string{}
+		for _, c := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields[c.Field] = c.Message
+		}
+		return fields
+	}
+
+	BeforeEach(func() {
+		obj = &djangov1alpha1.DjangoApp{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"}}
+		var err error
+		validator, err = NewDjangoAppCustomValidator(schemaChart)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("When creating or updating DjangoApp under Validating Webhook", func() {
+		It("Should admit values conforming to the schema", func() {
+			Expect(validator.ValidateCreate(ctx, withValues(`{"image":{"tag":"v2","pullPolicy":"Always"}}`))).
+				Error().NotTo(HaveOccurred())
+			By("taking the values the schema requires from the chart defaults")
+			Expect(validator.ValidateCreate(ctx, withValues(`{"djangoServer":{"replicaCount":3}}`))).
+				Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateCreate(ctx, &djangov1alpha1.DjangoApp{})).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny values violating the schema, with the path of each one", func() {
+			_, err := validator.ValidateCreate(ctx, withValues(`{
+				"image": {"pullPolicy": "Sometimes"},
+				"djangoServer": {"replicaCount": "three", "ingress": {"hosts": [{"host": "a"}, {"paths": ["/"]}]}}
+			}`))
+			fields := causes(err)
+			Expect(fields).To(HaveLen(3))
+			Expect(fields).To(HaveKeyWithValue("spec.values.image.pullPolicy", ContainSubstring("Sometimes")))
+			Expect(fields).To(HaveKeyWithValue("spec.values.djangoServer.replicaCount", ContainSubstring("want integer")))
+			Expect(fields).To(HaveKeyWithValue("spec.values.djangoServer.ingress.hosts[1]", ContainSubstring("host")))
+
+			By("validating updates that change the values")
+			_, err = validator.ValidateUpdate(ctx, &djangov1alpha1.DjangoApp{}, withValues(`{"image":{"repository":""}}`))
+			Expect(causes(err)).To(HaveKey("spec.values.image.repository"))
+
+			By("admitting updates that leave invalid values as they are")
+			updated := obj.DeepCopy()
+			updated.Finalizers = []string{"uninstall-helm-release"}
+			Expect(validator.ValidateUpdate(ctx, obj, updated)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should validate the typed fields merged over the values", func() {
+			obj.Spec.Replicas = ptr.To[int32](-1)
+			obj.Spec.Image = &djangov1alpha1.AppImage{Tag: "v2", PullPolicy: "Sometimes"}
+			_, err := validator.ValidateCreate(ctx, withValues(`{"image":{"repository":""}}`))
+			fields := causes(err)
+			Expect(fields).To(HaveLen(3))
+			Expect(fields).To(HaveKeyWithValue("spec.replicas", ContainSubstring("minimum")))
+			Expect(fields).To(HaveKeyWithValue("spec.image.pullPolicy", ContainSubstring("Sometimes")))
+			Expect(fields).To(HaveKey("spec.values.image.repository"))
+
+			By("letting the typed fields fix the values")
+			obj.Spec.Replicas = ptr.To[int32](2)
+			obj.Spec.Image = &djangov1alpha1.AppImage{Repository: "registry/shop"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			By("validating updates that only change the typed fields")
+			updated := obj.DeepCopy()
+			updated.Spec.Replicas = ptr.To[int32](-2)
+			_, err = validator.ValidateUpdate(ctx, obj, updated)
+			Expect(causes(err)).To(HaveKey("spec.replicas"))
+		})
+
+		It("Should deny values that are not an object", func() {
+			_, err := validator.ValidateCreate(ctx, withValues(`["image"]`))
+			Expect(causes(err)).To(HaveKey("spec.values"))
+		})
+
+		It("Should validate the embedded chart with the operator's schema", func() {
+			chartObj, err := chartWithSchema()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chartObj.Schema).NotTo(BeEmpty())
+			validator, err = NewDjangoAppCustomValidator(chartObj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validator.schema).NotTo(BeNil())
+
+			By("admitting the chart defaults and the sample")
+			Expect(validator.ValidateCreate(ctx, &djangov1alpha1.DjangoApp{})).Error().NotTo(HaveOccurred())
+			obj.Spec.Replicas = ptr.To[int32](1)
+			obj.Spec.Image = &djangov1alpha1.AppImage{Tag: "v2", PullPolicy: "IfNotPresent"}
+			obj.Spec.Ingress = &djangov1alpha1.AppIngress{
+				Hosts: []djangov1alpha1.IngressHost{{Host: "shop.example.com", Paths: []string{"/"}}},
+				TLS:   []djangov1alpha1.IngressTLS{{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"}},
+			}
+			obj.Spec.Celery = &djangov1alpha1.AppCelery{
+				Workers: []djangov1alpha1.CeleryWorker{{Name: "emails", Replicas: ptr.To[int32](2)}},
+				Beat:    &djangov1alpha1.CeleryBeat{},
+			}
+			Expect(validator.ValidateCreate(ctx, withValues(`{"configmap":{"data":{"DEBUG":false,"REDIS_PORT":6379}}}`))).
+				Error().NotTo(HaveOccurred())
+
+			By("denying values of the wrong type")
+			obj.Spec = djangov1alpha1.DjangoAppSpec{}
+			_, err = validator.ValidateCreate(ctx, withValues(`{
+				"djangoServer": {"replicaCount": "three", "service": {"port": 0}},
+				"celeryWorker": {"queue": [{"name": "Emails"}]}
+			}`))
+			fields := causes(err)
+			Expect(fields).To(HaveLen(3))
+			Expect(fields).To(HaveKey("spec.values.djangoServer.replicaCount"))
+			Expect(fields).To(HaveKey("spec.values.djangoServer.service.port"))
+			Expect(fields).To(HaveKey("spec.values.celeryWorker.queue[0].name"))
+		})
+	})
+})